@@ -0,0 +1,223 @@
+package dep
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/Masterminds/semver/v3"
+	"github.com/paketo-buildpacks/packit/v2/postal"
+)
+
+// DefaultArch is the architecture of dependencies in buildpack.toml that do
+// not declare one, as the first dep artifacts were only built for amd64.
+const DefaultArch = "amd64"
+
+// ErrNoPrebuiltDependency is matched by the error of
+// ArchDependencyManager.Resolve when a version satisfies the constraint, but
+// none is prebuilt for the stack and arch, so that it could still be built
+// from source.
+var ErrNoPrebuiltDependency = errors.New("no prebuilt dependency for the stack and arch")
+
+// noPrebuiltError keeps the message of the resolution error while matching
+// ErrNoPrebuiltDependency.
+type noPrebuiltError struct {
+	error
+}
+
+func (e noPrebuiltError) Is(target error) bool {
+	return target == ErrNoPrebuiltDependency
+}
+
+type archDependency struct {
+	postal.Dependency
+	Arch string `toml:"arch"`
+}
+
+type buildpackMetadata struct {
+	Metadata struct {
+		DefaultVersions map[string]string `toml:"default-versions"`
+		Dependencies    []archDependency  `toml:"dependencies"`
+	} `toml:"metadata"`
+}
+
+// ArchDependencyManager resolves dependencies by CPU architecture in addition
+// to stack. Delivery and bill of materials generation are left to the
+// embedded postal.Service.
+type ArchDependencyManager struct {
+	postal.Service
+}
+
+// NewArchDependencyManager wraps the given postal.Service. Build resolves
+// dependency mappings itself, to log them and to give them precedence over
+// mirrors, so the service delivers every dependency from its URI as it is
+// rather than resolving its mapping a second time.
+func NewArchDependencyManager(service postal.Service) ArchDependencyManager {
+	return ArchDependencyManager{
+		Service: service.WithDependencyMappingResolver(resolvedDependencyMappings{}),
+	}
+}
+
+// resolvedDependencyMappings is the mapping resolver of a postal.Service that
+// delivers dependencies whose mappings were already resolved.
+type resolvedDependencyMappings struct{}
+
+func (resolvedDependencyMappings) FindDependencyMapping(sha256, platformDir string) (string, error) {
+	return "", nil
+}
+
+// Resolve picks the newest dependency in the buildpack.toml at path with the
+// given id that satisfies the version constraint and is built for the given
+// stack and arch. Dependencies without an arch are treated as DefaultArch.
+// When a version only satisfies the constraint on another stack or arch, the
+// error matches ErrNoPrebuiltDependency.
+func (m ArchDependencyManager) Resolve(path, id, version, stack, arch string) (postal.Dependency, error) {
+	buildpack, err := parseBuildpackMetadata(path)
+	if err != nil {
+		return postal.Dependency{}, err
+	}
+
+	version = normalizeConstraint(version, buildpack.Metadata.DefaultVersions[id])
+	constraint, err := semver.NewConstraint(version)
+	if err != nil {
+		return postal.Dependency{}, err
+	}
+
+	var (
+		compatible        []postal.Dependency
+		supportedVersions []string
+		otherArches       []string
+		otherStacks       bool
+	)
+	for _, dependency := range buildpack.Metadata.Dependencies {
+		if dependency.ID != id {
+			continue
+		}
+
+		if !stacksInclude(dependency.Stacks, stack) {
+			if v, err := semver.NewVersion(dependency.Version); err == nil && constraint.Check(v) {
+				otherStacks = true
+			}
+			continue
+		}
+
+		v, err := semver.NewVersion(dependency.Version)
+		if err != nil {
+			return postal.Dependency{}, err
+		}
+
+		dependencyArch := dependency.Arch
+		if dependencyArch == "" {
+			dependencyArch = DefaultArch
+		}
+
+		if dependencyArch != arch {
+			if constraint.Check(v) && !contains(otherArches, dependencyArch) {
+				otherArches = append(otherArches, dependencyArch)
+			}
+			continue
+		}
+
+		supportedVersions = append(supportedVersions, dependency.Version)
+		if constraint.Check(v) {
+			compatible = append(compatible, dependency.Dependency)
+		}
+	}
+
+	if len(compatible) == 0 {
+		if len(otherArches) > 0 {
+			sort.Strings(otherArches)
+			return postal.Dependency{}, noPrebuiltError{fmt.Errorf(
+				"failed to satisfy %q dependency version constraint %q: no compatible versions for the %q architecture on %q stack. Available architectures are: [%s]",
+				id, version, arch, stack, strings.Join(otherArches, ", "),
+			)}
+		}
+
+		err := fmt.Errorf(
+			"failed to satisfy %q dependency version constraint %q: no compatible versions for the %q architecture on %q stack. Supported versions are: [%s]",
+			id, version, arch, stack, strings.Join(supportedVersions, ", "),
+		)
+		if otherStacks {
+			return postal.Dependency{}, noPrebuiltError{err}
+		}
+
+		return postal.Dependency{}, err
+	}
+
+	sort.Slice(compatible, func(i, j int) bool {
+		return semver.MustParse(compatible[i].Version).GreaterThan(semver.MustParse(compatible[j].Version))
+	})
+
+	return compatible[0], nil
+}
+
+// TargetArch returns the CPU architecture that the build is targeting, as
+// given by the platform, falling back to the architecture the buildpack is
+// running on.
+func TargetArch() string {
+	if arch, ok := os.LookupEnv("CNB_TARGET_ARCH"); ok && arch != "" {
+		return arch
+	}
+
+	return runtime.GOARCH
+}
+
+func parseBuildpackMetadata(path string) (buildpackMetadata, error) {
+	var buildpack buildpackMetadata
+	_, err := toml.DecodeFile(path, &buildpack)
+	if err != nil {
+		return buildpackMetadata{}, fmt.Errorf("failed to parse buildpack.toml: %w", err)
+	}
+
+	return buildpack, nil
+}
+
+// normalizeConstraint resolves the "default" version and the pessimistic
+// operator (~>) the same way that postal.Service does.
+func normalizeConstraint(version, defaultVersion string) string {
+	if version == "" || version == "default" {
+		version = "*"
+		if defaultVersion != "" {
+			version = defaultVersion
+		}
+	}
+
+	pessimistic := regexp.MustCompile(`~>`)
+	if pessimistic.MatchString(version) {
+		res := pessimistic.ReplaceAllString(version, "")
+		parts := strings.Split(res, ".")
+
+		if len(parts) == 3 {
+			version = fmt.Sprintf("~%s", res)
+		} else {
+			version = fmt.Sprintf("^%s", res)
+		}
+	}
+
+	return version
+}
+
+func stacksInclude(stacks []string, stack string) bool {
+	for _, s := range stacks {
+		if s == stack || s == "*" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
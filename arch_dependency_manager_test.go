@@ -0,0 +1,192 @@
+package dep_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/paketo-buildpacks/dep"
+	"github.com/paketo-buildpacks/dep/fakes"
+	"github.com/paketo-buildpacks/packit/v2/postal"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testArchDependencyManager(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path      string
+		transport *fakes.Transport
+		manager   dep.ArchDependencyManager
+	)
+
+	it.Before(func() {
+		file, err := os.CreateTemp("", "buildpack.toml")
+		Expect(err).NotTo(HaveOccurred())
+		path = file.Name()
+
+		_, err = file.WriteString(`
+[metadata]
+  [metadata.default-versions]
+    dep = "0.5.*"
+
+  [[metadata.dependencies]]
+    arch = "amd64"
+    id = "dep"
+    sha256 = "some-amd64-sha"
+    stacks = ["some-stack"]
+    uri = "some-amd64-uri"
+    version = "0.5.4"
+
+  [[metadata.dependencies]]
+    arch = "arm64"
+    id = "dep"
+    sha256 = "some-arm64-sha"
+    stacks = ["some-stack"]
+    uri = "some-arm64-uri"
+    version = "0.5.4"
+
+  [[metadata.dependencies]]
+    id = "dep"
+    sha256 = "some-other-sha"
+    stacks = ["some-other-stack"]
+    uri = "some-other-uri"
+    version = "0.5.3"
+
+  [[metadata.dependencies]]
+    arch = "amd64"
+    id = "dep"
+    sha256 = "some-newer-sha"
+    stacks = ["*"]
+    uri = "some-newer-uri"
+    version = "0.6.0"
+`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(file.Close()).To(Succeed())
+
+		transport = &fakes.Transport{}
+		manager = dep.NewArchDependencyManager(postal.NewService(transport))
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(path)).To(Succeed())
+	})
+
+	context("Resolve", func() {
+		it("returns the dependency built for the given architecture", func() {
+			dependency, err := manager.Resolve(path, "dep", "default", "some-stack", "arm64")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dependency).To(Equal(postal.Dependency{
+				ID:      "dep",
+				SHA256:  "some-arm64-sha",
+				Stacks:  []string{"some-stack"},
+				URI:     "some-arm64-uri",
+				Version: "0.5.4",
+			}))
+
+			dependency, err = manager.Resolve(path, "dep", "default", "some-stack", "amd64")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dependency.SHA256).To(Equal("some-amd64-sha"))
+		})
+
+		it("returns the newest version that satisfies the constraint", func() {
+			dependency, err := manager.Resolve(path, "dep", "*", "some-stack", "amd64")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dependency.SHA256).To(Equal("some-newer-sha"))
+		})
+
+		context("when the dependency does not declare an architecture", func() {
+			it("is treated as an amd64 dependency", func() {
+				dependency, err := manager.Resolve(path, "dep", "~> 0.5.3", "some-other-stack", "amd64")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dependency.SHA256).To(Equal("some-other-sha"))
+			})
+		})
+
+		context("failure cases", func() {
+			context("when there is no dependency for the architecture", func() {
+				it("returns an error naming the available architectures", func() {
+					_, err := manager.Resolve(path, "dep", "0.5.4", "some-stack", "ppc64le")
+					Expect(err).To(MatchError(`failed to satisfy "dep" dependency version constraint "0.5.4": no compatible versions for the "ppc64le" architecture on "some-stack" stack. Available architectures are: [amd64, arm64]`))
+					Expect(errors.Is(err, dep.ErrNoPrebuiltDependency)).To(BeTrue())
+				})
+			})
+
+			context("when the dependency is only prebuilt for another stack", func() {
+				it("returns an error that allows building it from source", func() {
+					_, err := manager.Resolve(path, "dep", "0.5.3", "some-stack", "amd64")
+					Expect(err).To(MatchError(ContainSubstring(`no compatible versions for the "amd64" architecture on "some-stack" stack`)))
+					Expect(errors.Is(err, dep.ErrNoPrebuiltDependency)).To(BeTrue())
+				})
+			})
+
+			context("when there is no dependency that satisfies the constraint", func() {
+				it("returns an error listing the supported versions", func() {
+					_, err := manager.Resolve(path, "dep", "0.4.*", "some-stack", "arm64")
+					Expect(err).To(MatchError(`failed to satisfy "dep" dependency version constraint "0.4.*": no compatible versions for the "arm64" architecture on "some-stack" stack. Supported versions are: [0.5.4]`))
+					Expect(errors.Is(err, dep.ErrNoPrebuiltDependency)).To(BeFalse())
+				})
+			})
+
+			context("when the buildpack.toml cannot be parsed", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(path, []byte("%%%"), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := manager.Resolve(path, "dep", "default", "some-stack", "amd64")
+					Expect(err).To(MatchError(ContainSubstring("failed to parse buildpack.toml")))
+				})
+			})
+
+			context("when the version constraint is invalid", func() {
+				it("returns an error", func() {
+					_, err := manager.Resolve(path, "dep", "not-a-constraint", "some-stack", "amd64")
+					Expect(err).To(MatchError(ContainSubstring("improper constraint")))
+				})
+			})
+		})
+	})
+
+	context("Deliver", func() {
+		var platformDir string
+
+		it.Before(func() {
+			var err error
+			platformDir, err = os.MkdirTemp("", "platform")
+			Expect(err).NotTo(HaveOccurred())
+
+			bindingDir := filepath.Join(platformDir, "bindings", "some-mapping")
+			Expect(os.MkdirAll(bindingDir, os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(bindingDir, "type"), []byte("dependency-mapping"), 0600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(bindingDir, "some-sha"), []byte("some-mapped-uri"), 0600)).To(Succeed())
+
+			transport.DropCall.Returns.Error = errors.New("failed to drop")
+		})
+
+		it.After(func() {
+			Expect(os.RemoveAll(platformDir)).To(Succeed())
+		})
+
+		it("downloads the dependency from its URI without resolving its mapping again", func() {
+			err := manager.Deliver(postal.Dependency{
+				ID:     "dep",
+				SHA256: "some-sha",
+				URI:    "some-uri",
+			}, "some-cnb-path", "some-layer-path", platformDir)
+			Expect(err).To(MatchError(ContainSubstring("failed to drop")))
+
+			Expect(transport.DropCall.Receives.Uri).To(Equal("some-uri"))
+		})
+	})
+
+	context("TargetArch", func() {
+		it("defaults to the architecture the buildpack runs on", func() {
+			Expect(dep.TargetArch()).To(Equal(runtime.GOARCH))
+		})
+	})
+}
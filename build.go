@@ -1,11 +1,20 @@
 package dep
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 	"time"
+	"unicode"
 
 	"github.com/paketo-buildpacks/packit/v2"
 	"github.com/paketo-buildpacks/packit/v2/chronos"
+	"github.com/paketo-buildpacks/packit/v2/fs"
 	"github.com/paketo-buildpacks/packit/v2/postal"
 	"github.com/paketo-buildpacks/packit/v2/sbom"
 	"github.com/paketo-buildpacks/packit/v2/scribe"
@@ -19,20 +28,113 @@ type EntryResolver interface {
 
 //go:generate faux --interface DependencyManager --output fakes/dependency_manager.go
 type DependencyManager interface {
-	Resolve(path, id, version, stack string) (postal.Dependency, error)
+	Resolve(path, id, version, stack, arch string) (postal.Dependency, error)
 	Deliver(dependency postal.Dependency, cnbPath, layerPath, platformPath string) error
 	GenerateBillOfMaterials(dependencies ...postal.Dependency) []packit.BOMEntry
 }
 
+//go:generate faux --interface MappingResolver --output fakes/mapping_resolver.go
+type MappingResolver interface {
+	FindDependencyMapping(sha256, platformDir string) (string, error)
+}
+
+//go:generate faux --interface MirrorResolver --output fakes/mirror_resolver.go
+type MirrorResolver interface {
+	FindDependencyMirror(uri, platformDir string) (string, error)
+}
+
+//go:generate faux --interface SourceBuilder --output fakes/source_builder.go
+type SourceBuilder interface {
+	Resolve(path, id, version string) (postal.Dependency, error)
+	Build(dependency postal.Dependency, cnbPath, layerPath string) error
+}
+
+//go:generate faux --interface EnsureProcess --output fakes/ensure_process.go
+type EnsureProcess interface {
+	Execute(gopath, importPath, binPath, cachePath string, env []string) error
+}
+
+//go:generate faux --interface VendorPruner --output fakes/vendor_pruner.go
+type VendorPruner interface {
+	Prune(workingDir, vendorDir string, options []string) error
+}
+
+//go:generate faux --interface CheckProcess --output fakes/check_process.go
+type CheckProcess interface {
+	Execute(gopath, importPath, binPath string) error
+}
+
+//go:generate faux --interface VendorVerifier --output fakes/vendor_verifier.go
+type VendorVerifier interface {
+	Verify(workingDir string) ([]VendorChange, error)
+}
+
+//go:generate faux --interface CredentialsResolver --output fakes/credentials_resolver.go
+type CredentialsResolver interface {
+	Resolve(platformDir, homeDir string) (GitCredentials, error)
+}
+
+//go:generate faux --interface SourceRewriter --output fakes/source_rewriter.go
+type SourceRewriter interface {
+	Resolve(platformDir string) ([]SourceRewrite, error)
+	WriteGitConfig(path string, rewrites []SourceRewrite) error
+	WriteNetrc(path string, rewrites []SourceRewrite) error
+}
+
+//go:generate faux --interface ModuleMigrator --output fakes/module_migrator.go
+type ModuleMigrator interface {
+	Migrate(workingDir, cacheDir, modulePath string) (ModuleMigration, error)
+}
+
+//go:generate faux --interface VendorModulesGenerator --output fakes/vendor_modules_generator.go
+type VendorModulesGenerator interface {
+	Generate(workingDir, modulePath string) (VendorModules, error)
+}
+
+//go:generate faux --interface VulnerabilityScanner --output fakes/vulnerability_scanner.go
+type VulnerabilityScanner interface {
+	Scan(workingDir, platformDir string) (VulnerabilityScan, error)
+}
+
+//go:generate faux --interface LicenseInventory --output fakes/license_inventory.go
+type LicenseInventory interface {
+	Collect(workingDir string) ([]ProjectLicenses, error)
+}
+
+//go:generate faux --interface ImportPathResolver --output fakes/import_path_resolver.go
+type ImportPathResolver interface {
+	Resolve(workingDir string) (importPath string, source string, err error)
+}
+
 //go:generate faux --interface SBOMGenerator --output fakes/sbom_generator.go
 type SBOMGenerator interface {
 	GenerateFromDependency(dependency postal.Dependency, dir string) (sbom.SBOM, error)
 }
 
+//go:generate faux --interface VendorSBOMGenerator --output fakes/vendor_sbom_generator.go
+type VendorSBOMGenerator interface {
+	Generate(workingDir string) (sbom.SBOM, error)
+}
+
 func Build(
 	entryResolver EntryResolver,
 	dependencyManager DependencyManager,
+	mappingResolver MappingResolver,
+	mirrorResolver MirrorResolver,
+	sourceBuilder SourceBuilder,
+	importPathResolver ImportPathResolver,
+	credentialsResolver CredentialsResolver,
+	sourceRewriter SourceRewriter,
+	checkProcess CheckProcess,
+	vendorVerifier VendorVerifier,
+	ensureProcess EnsureProcess,
+	vendorPruner VendorPruner,
+	moduleMigrator ModuleMigrator,
+	vendorModulesGenerator VendorModulesGenerator,
+	vulnerabilityScanner VulnerabilityScanner,
+	licenseInventory LicenseInventory,
 	sbomGenerator SBOMGenerator,
+	vendorSBOMGenerator VendorSBOMGenerator,
 	clock chronos.Clock,
 	logger scribe.Emitter,
 ) packit.BuildFunc {
@@ -40,22 +142,164 @@ func Build(
 		logger.Title("%s %s", context.BuildpackInfo.Name, context.BuildpackInfo.Version)
 		logger.Break()
 
-		entry, _ := entryResolver.Resolve(Dep, context.Plan.Entries, nil)
+		// BP_DEP_VERSION accepts any semver constraint that the dependency
+		// manager understands, for example "0.5.*" or "~0.5".
+		if version, ok := os.LookupEnv("BP_DEP_VERSION"); ok {
+			context.Plan.Entries = append(context.Plan.Entries, packit.BuildpackPlanEntry{
+				Name: Dep,
+				Metadata: map[string]interface{}{
+					"version-source": "BP_DEP_VERSION",
+					"version":        version,
+				},
+			})
+		}
+
+		entry, entries := entryResolver.Resolve(Dep, context.Plan.Entries, []interface{}{
+			"BP_DEP_VERSION",
+			"buildpack.yml",
+			GopkgToml,
+		})
+		logger.Candidates(entries)
 
 		version, ok := entry.Metadata["version"].(string)
 		if !ok {
 			version = "default"
 		}
 
+		// Every requested constraint must hold for the selected version, not
+		// just the one with the highest priority.
+		constraints := versionConstraints(entries)
+		if len(constraints) > 1 {
+			version = strings.Join(constraints, ", ")
+		}
+
+		buildFromSource, err := parseBoolEnv("BP_DEP_BUILD_FROM_SOURCE")
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		check, err := parseBoolEnv("BP_DEP_CHECK")
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		migrate := os.Getenv("BP_DEP_MIGRATE")
+		if migrate != "" && migrate != "report" && migrate != "apply" {
+			return packit.BuildResult{}, fmt.Errorf("failed to parse BP_DEP_MIGRATE: %q is not one of report or apply", migrate)
+		}
+
+		vendorModules, err := parseBoolEnv("BP_DEP_VENDOR_MODULES")
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		if vendorModules && migrate == "apply" {
+			return packit.BuildResult{}, fmt.Errorf("BP_DEP_VENDOR_MODULES cannot be combined with BP_DEP_MIGRATE=apply, as both write a go.mod into the app")
+		}
+
+		denyList := strings.FieldsFunc(os.Getenv("BP_DEP_LICENSE_DENY_LIST"), func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+
+		// Without a threshold, vulnerabilities are reported but never fail the
+		// build.
+		var threshold Severity
+		if value, ok := os.LookupEnv("BP_DEP_VULNERABILITY_THRESHOLD"); ok {
+			threshold, err = ParseSeverity(value)
+			if err != nil {
+				return packit.BuildResult{}, fmt.Errorf("failed to parse BP_DEP_VULNERABILITY_THRESHOLD: %w", err)
+			}
+		}
+
+		// A dep-vendor entry is only in the plan when a later buildpack requires
+		// the vendor tree, which is then also provided in a layer of its own.
+		var (
+			vendorRequired            bool
+			vendorLaunch, vendorBuild bool
+			pruneOptions              []string
+		)
+		for _, e := range context.Plan.Entries {
+			if e.Name == DepVendor {
+				vendorRequired = true
+			}
+		}
+
+		if vendorRequired {
+			_, vendorEntries := entryResolver.Resolve(DepVendor, context.Plan.Entries, nil)
+
+			var prune []string
+			for _, e := range vendorEntries {
+				value, ok := e.Metadata["prune"]
+				if !ok {
+					continue
+				}
+
+				option, ok := value.(string)
+				if !ok {
+					return packit.BuildResult{}, fmt.Errorf("failed to parse dep-vendor prune metadata: %v is not a string", value)
+				}
+
+				prune = append(prune, option)
+			}
+
+			pruneOptions, err = ParsePruneOptions(strings.Join(prune, ","))
+			if err != nil {
+				return packit.BuildResult{}, fmt.Errorf("failed to parse dep-vendor prune metadata: %w", err)
+			}
+
+			vendorLaunch, vendorBuild = entryResolver.MergeLayerTypes(DepVendor, context.Plan.Entries)
+		}
+
+		arch := TargetArch()
+
+		var fromSource bool
 		dependency, err := dependencyManager.Resolve(
 			filepath.Join(context.CNBPath, "buildpack.toml"),
 			entry.Name,
 			version,
-			context.Stack)
+			context.Stack,
+			arch)
 		if err != nil {
-			return packit.BuildResult{}, err
+			if len(constraints) > 1 {
+				err = fmt.Errorf("failed to find a dep version that satisfies all requested version constraints (%s): %w", strings.Join(constraints, ", "), err)
+			}
+
+			// Only a version that is missing a prebuilt artifact for this stack and
+			// arch can be built from source instead.
+			if !buildFromSource || !errors.Is(err, ErrNoPrebuiltDependency) {
+				return packit.BuildResult{}, err
+			}
+
+			logger.Process("No prebuilt dep matches the %s stack on %s, falling back to building from source", context.Stack, arch)
+			logger.Break()
+
+			dependency, err = sourceBuilder.Resolve(filepath.Join(context.CNBPath, "buildpack.toml"), entry.Name, version)
+			if err != nil {
+				return packit.BuildResult{}, fmt.Errorf("failed to resolve dep source: %w", err)
+			}
+
+			fromSource = true
+		}
+
+		// A dep built from source is identified by its source checksum, as the
+		// prebuilt artifact checksum belongs to another stack.
+		cacheKey := dependency.SHA256
+		if fromSource {
+			cacheKey = dependency.SourceSHA256
+		}
+
+		logger.SelectedDependency(entry, dependency, clock.Now())
+
+		// The BOM and SBOM describe the artifact that dep was installed from,
+		// which is the source artifact when it is built from source.
+		installed := dependency
+		if fromSource {
+			installed.SHA256 = dependency.SourceSHA256
+			installed.Checksum = dependency.SourceChecksum
+			installed.URI = dependency.Source
 		}
-		bom := dependencyManager.GenerateBillOfMaterials(dependency)
+
+		bom := dependencyManager.GenerateBillOfMaterials(installed)
 
 		launch, build := entryResolver.MergeLayerTypes(Dep, context.Plan.Entries)
 
@@ -74,45 +318,152 @@ func Build(
 			launchMetadata = packit.LaunchMetadata{BOM: bom}
 		}
 
-		cachedSHA, ok := depLayer.Metadata[DependencyCacheKey].(string)
-		if ok && cachedSHA == dependency.SHA256 {
+		// The checksum alone does not identify the layer contents, as a cache
+		// may be restored onto a builder of another architecture or stack, or
+		// have been installed by another version of the buildpack.
+		uri := dependency.URI
+		if fromSource {
+			uri = dependency.Source
+		}
+
+		// Later buildpacks that run dep find its home, cache and locking mode in
+		// the environment of the dep layer. A reused layer keeps the environment
+		// it was installed with, so the environment is part of its key.
+		environment := packit.Layer{Path: depLayer.Path}
+		cacheExported, err := exportDepEnvironment(&environment, filepath.Join(context.Layers.Path, DepCache))
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		layerKey := []layerKeyField{
+			{DependencyCacheKey, cacheKey},
+			{DependencyArchKey, arch},
+			{DependencyVersionKey, dependency.Version},
+			{DependencyURIKey, uri},
+			{StackKey, context.Stack},
+			{BuildpackVersionKey, context.BuildpackInfo.Version},
+			{EnvironmentKey, environmentKey(environment)},
+		}
+
+		// A layer that matches the key is only reused once its files are verified,
+		// as the cache volume it was restored from may have been damaged. A layer
+		// that is not cached is restored without its files, as it is reused from
+		// the previous image, so there is nothing to verify.
+		changes := layerKeyChanges(depLayer.Metadata, layerKey)
+		if len(changes) == 0 && build {
+			changes, err = layerManifestChanges(depLayer.Path, depLayer.Metadata[LayerManifestKey])
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+		}
+
+		if len(changes) == 0 {
 			logger.Process("Reusing cached layer %s", depLayer.Path)
 			logger.Break()
 
 			depLayer.Launch, depLayer.Build, depLayer.Cache = launch, build, build
+		} else {
+			logger.Process("Executing build process")
 
-			return packit.BuildResult{
-				Layers: []packit.Layer{depLayer},
-				Build:  buildMetadata,
-				Launch: launchMetadata,
-			}, nil
-		}
+			// A layer without metadata was never installed, so there is nothing
+			// to explain.
+			if len(depLayer.Metadata) > 0 {
+				for _, change := range changes {
+					logger.Subprocess("Reinstalling dep, as %s", change)
+				}
+			}
 
-		logger.Process("Executing build process")
+			depLayer, err = depLayer.Reset()
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
 
-		depLayer, err = depLayer.Reset()
-		if err != nil {
-			return packit.BuildResult{}, err
-		}
+			depLayer.Launch, depLayer.Build, depLayer.Cache = launch, build, build
 
-		depLayer.Launch, depLayer.Build, depLayer.Cache = launch, build, build
+			var duration time.Duration
+			if fromSource {
+				logger.Subprocess("Building Dep from source")
 
-		logger.Subprocess("Installing Dep")
+				duration, err = clock.Measure(func() error {
+					return sourceBuilder.Build(dependency, context.CNBPath, depLayer.Path)
+				})
+				if err != nil {
+					return packit.BuildResult{}, err
+				}
+			} else {
+				// The download location is redirected on a copy of the dependency so
+				// that the BOM and SBOM keep describing the canonical artifact.
+				delivery := dependency
 
-		duration, err := clock.Measure(func() error {
-			return dependencyManager.Deliver(dependency, context.CNBPath, depLayer.Path, context.Platform.Path)
-		})
-		if err != nil {
-			return packit.BuildResult{}, err
-		}
+				mappingURI, err := mappingResolver.FindDependencyMapping(dependency.SHA256, context.Platform.Path)
+				if err != nil {
+					return packit.BuildResult{}, err
+				}
 
-		logger.Action("Completed in %s", duration.Round(time.Millisecond))
-		logger.Break()
+				if mappingURI != "" {
+					logger.Subprocess("Using dependency mapping: %s", redactURI(mappingURI))
+					delivery.URI = mappingURI
+				} else {
+					mirrorURI, err := mirrorResolver.FindDependencyMirror(dependency.URI, context.Platform.Path)
+					if err != nil {
+						return packit.BuildResult{}, err
+					}
+
+					if mirrorURI != "" {
+						logger.Subprocess("Using dependency mirror: %s", redactURI(mirrorURI))
+						delivery.URI = mirrorURI
+					}
+				}
+
+				logger.Subprocess("Installing Dep")
 
+				duration, err = clock.Measure(func() error {
+					return dependencyManager.Deliver(delivery, context.CNBPath, depLayer.Path, context.Platform.Path)
+				})
+				if err != nil {
+					return packit.BuildResult{}, err
+				}
+			}
+
+			logger.Action("Completed in %s", duration.Round(time.Millisecond))
+			logger.Break()
+
+			depLayer.SharedEnv, depLayer.BuildEnv = environment.SharedEnv, environment.BuildEnv
+
+			// The receipt is written before the manifest is recorded, so that it is
+			// verified along with the rest of the layer when it is reused.
+			err = writeDepReceipt(filepath.Join(depLayer.Path, DepReceiptFile), DepReceipt{
+				Version:         dependency.Version,
+				SHA256:          cacheKey,
+				URI:             uri,
+				BuiltFromSource: fromSource,
+				Arch:            arch,
+				Path:            depLayer.Path,
+			})
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			manifest, err := layerManifest(depLayer.Path)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			depLayer.Metadata = map[string]interface{}{
+				LayerManifestKey: manifest,
+			}
+			for _, field := range layerKey {
+				depLayer.Metadata[field.Key] = field.Value
+			}
+		}
+
+		// The SBOM files of a layer are not restored with it, so the SBOM is
+		// generated for reused layers as well. It only depends on the dependency
+		// and the layer path, so it is the same as when the layer was installed.
 		logger.GeneratingSBOM(depLayer.Path)
 		var sbomContent sbom.SBOM
-		duration, err = clock.Measure(func() error {
-			sbomContent, err = sbomGenerator.GenerateFromDependency(dependency, depLayer.Path)
+		duration, err := clock.Measure(func() error {
+			sbomContent, err = sbomGenerator.GenerateFromDependency(installed, depLayer.Path)
 			return err
 		})
 		if err != nil {
@@ -128,14 +479,928 @@ func Build(
 			return packit.BuildResult{}, err
 		}
 
-		depLayer.Metadata = map[string]interface{}{
-			DependencyCacheKey: dependency.SHA256,
+		logger.EnvironmentVariables(environment)
+
+		// A checked-in vendor directory is verified without running dep, so it
+		// can be trusted even when dep is only installed for later buildpacks.
+		vendored, err := fs.Exists(filepath.Join(context.WorkingDir, "vendor"))
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		locked, err := fs.Exists(filepath.Join(context.WorkingDir, GopkgLock))
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		if vendored && locked {
+			logger.Process("Verifying vendor against Gopkg.lock")
+
+			changes, err := vendorVerifier.Verify(context.WorkingDir)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			if len(changes) == 0 {
+				logger.Subprocess("vendor matches Gopkg.lock")
+			}
+
+			for _, change := range changes {
+				logger.Subprocess("%s: %s", change.Project, change.Change)
+				for _, pkg := range change.Packages {
+					logger.Action(pkg)
+				}
+			}
+			logger.Break()
+
+			if len(changes) > 0 && check {
+				return packit.BuildResult{}, fmt.Errorf("vendor does not match Gopkg.lock: %d project(s) changed, run 'dep ensure' and commit the result", len(changes))
+			}
+		}
+
+		exists, err := fs.Exists(filepath.Join(context.WorkingDir, GopkgToml))
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		if vendorRequired && !exists {
+			return packit.BuildResult{}, fmt.Errorf("failed to provide dep-vendor: %s does not exist", GopkgToml)
+		}
+
+		layers := []packit.Layer{depLayer}
+		if exists {
+			logger.Process("Vendoring dependencies")
+
+			cacheLayer, err := context.Layers.Get(DepCache)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			lockDigest, err := gopkgLockDigest(context.WorkingDir)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			cachedDigest, ok := cacheLayer.Metadata[LockDigestKey].(string)
+			switch {
+			case !ok:
+				cacheLayer, err = cacheLayer.Reset()
+				if err != nil {
+					return packit.BuildResult{}, err
+				}
+			case cachedDigest == lockDigest:
+				logger.Subprocess("Reusing cached dep sources %s", cacheLayer.Path)
+			default:
+				logger.Subprocess("Gopkg.lock has changed, refreshing cached dep sources %s", cacheLayer.Path)
+			}
+
+			err = os.MkdirAll(cacheLayer.Path, os.ModePerm)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			importPath, source, err := importPathResolver.Resolve(context.WorkingDir)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			if source != "" {
+				logger.Subprocess("Using import path %s from %s", importPath, source)
+			} else {
+				logger.Subprocess("No import path configured, using %s", importPath)
+			}
+
+			// dep only works inside of a GOPATH, so the app is linked into a
+			// GOPATH layer at its import path. The layer is also exported to later
+			// buildpacks so that go build sees the same workspace as dep.
+			gopathLayer, err := context.Layers.Get(Gopath)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			gopathLayer, err = gopathLayer.Reset()
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			appPath := filepath.Join(gopathLayer.Path, "src", filepath.FromSlash(importPath))
+			err = os.MkdirAll(filepath.Dir(appPath), os.ModePerm)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			err = os.Symlink(context.WorkingDir, appPath)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			gopathLayer.Build = true
+			gopathLayer.BuildEnv.Override("GOPATH", gopathLayer.Path)
+
+			// Once the app is migrated or has a synthesized go.mod, later
+			// buildpacks build it as a module.
+			if migrate != "apply" && !vendorModules {
+				gopathLayer.BuildEnv.Override("GO111MODULE", "off")
+			}
+
+			logger.Subprocess("Linked app into GOPATH at %s", appPath)
+
+			// The Go build buildpack reads the packages to build from
+			// BP_GO_TARGETS, so the heroku install packages become its default.
+			targets, ignored := goTargets(context.Plan.Entries, importPath)
+			for _, pkg := range ignored {
+				logger.Subprocess("Ignoring install package %s, as it is not part of %s", pkg, importPath)
+			}
+
+			if len(targets) > 0 {
+				gopathLayer.BuildEnv.Default("BP_GO_TARGETS", strings.Join(targets, ":"))
+				logger.Subprocess("Using BP_GO_TARGETS %s from Gopkg.toml", strings.Join(targets, ":"))
+			}
+
+			// The check runs before dep ensure, which would otherwise bring the
+			// Gopkg.lock and vendor back in sync and hide the drift.
+			if check {
+				logger.Subprocess("Running 'dep check'")
+
+				duration, err := clock.Measure(func() error {
+					return checkProcess.Execute(gopathLayer.Path, importPath, filepath.Join(depLayer.Path, "bin"))
+				})
+				if err != nil {
+					return packit.BuildResult{}, err
+				}
+
+				logger.Action("Completed in %s", duration.Round(time.Millisecond))
+			}
+
+			// Credentials are written outside of the layers directory and removed
+			// as soon as dep ensure has run, so that they never end up in the image
+			// or the cache and are not available to any later step. The deferred
+			// removal only covers the steps that fail before dep ensure.
+			credentialsDir, err := os.MkdirTemp("", "dep-credentials")
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+			defer os.RemoveAll(credentialsDir)
+
+			credentials, err := credentialsResolver.Resolve(context.Platform.Path, credentialsDir)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			for _, name := range credentials.Bindings {
+				logger.Subprocess("Using git credentials from binding %s", name)
+			}
+
+			env := credentials.Env
+
+			rewrites, err := sourceRewriter.Resolve(context.Platform.Path)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			if len(rewrites) > 0 {
+				// dep ensure reads the rewrites from the .gitconfig in its HOME, which
+				// every version of git does, and the credentials of their urls from
+				// the .netrc next to it.
+				err = sourceRewriter.WriteNetrc(filepath.Join(credentialsDir, ".netrc"), rewrites)
+				if err != nil {
+					return packit.BuildResult{}, err
+				}
+
+				err = sourceRewriter.WriteGitConfig(filepath.Join(credentialsDir, ".gitconfig"), rewrites)
+				if err != nil {
+					return packit.BuildResult{}, err
+				}
+
+				if !hasEnv(env, "HOME") {
+					env = append([]string{fmt.Sprintf("HOME=%s", credentialsDir)}, env...)
+				}
+
+				// The rewrites are also kept in a build-only layer, so that later
+				// buildpacks fetch from the same sources as dep. The urls in it never
+				// carry credentials.
+				gitConfigLayer, err := context.Layers.Get(GitConfig)
+				if err != nil {
+					return packit.BuildResult{}, err
+				}
+
+				gitConfigLayer, err = gitConfigLayer.Reset()
+				if err != nil {
+					return packit.BuildResult{}, err
+				}
+
+				gitConfigPath := filepath.Join(gitConfigLayer.Path, "gitconfig")
+				err = sourceRewriter.WriteGitConfig(gitConfigPath, rewrites)
+				if err != nil {
+					return packit.BuildResult{}, err
+				}
+
+				for _, rewrite := range rewrites {
+					logger.Subprocess("Rewriting sources of %s to %s", rewrite.Prefix, redactURI(rewrite.URL))
+				}
+
+				gitConfigLayer.Build = true
+				gitConfigLayer.BuildEnv.Override("GIT_CONFIG_GLOBAL", gitConfigPath)
+
+				layers = append(layers, gitConfigLayer)
+			}
+
+			// The layer key is taken from the Gopkg.lock that the vendor tree is
+			// produced from, before dep ensure has had a chance to update it.
+			var vendorLayer packit.Layer
+			vendorKey := []layerKeyField{
+				{LockDigestKey, lockDigest},
+				{PruneKey, strings.Join(pruneOptions, ",")},
+			}
+			if vendorRequired {
+				vendorLayer, err = context.Layers.Get(DepVendor)
+				if err != nil {
+					return packit.BuildResult{}, err
+				}
+
+				// A pruned vendor tree no longer matches the digests in the Gopkg.lock,
+				// so dep would vendor it again in full rather than bring it up to date.
+				if lockDigest != "" && len(pruneOptions) == 0 {
+					err = restoreVendor(context.WorkingDir, vendorLayer, vendorKey, logger)
+					if err != nil {
+						return packit.BuildResult{}, err
+					}
+				}
+			}
+
+			logger.Subprocess("Running 'dep ensure'")
+
+			duration, err := clock.Measure(func() error {
+				return ensureProcess.Execute(gopathLayer.Path, importPath, filepath.Join(depLayer.Path, "bin"), cacheLayer.Path, env)
+			})
+
+			removeErr := os.RemoveAll(credentialsDir)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			if removeErr != nil {
+				return packit.BuildResult{}, fmt.Errorf("failed to remove git credentials: %w", removeErr)
+			}
+
+			logger.Action("Completed in %s", duration.Round(time.Millisecond))
+			logger.Break()
+
+			// dep ensure may have generated or updated the Gopkg.lock, so the
+			// digest is recalculated to describe the sources that are now cached.
+			ensuredDigest, err := gopkgLockDigest(context.WorkingDir)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			vendorSBOM, err := generateVendorSBOM(context, vendorSBOMGenerator, clock, logger)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			logger.FormattingSBOM(context.BuildpackInfo.SBOMFormats...)
+			launchMetadata.SBOM, err = vendorSBOM.InFormats(context.BuildpackInfo.SBOMFormats...)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			if vendorRequired {
+				// The vendor tree of a layer that matches the key was produced from
+				// the same Gopkg.lock, so it is still current unless dep ensure has
+				// changed it.
+				reuse := lockDigest != "" && lockDigest == ensuredDigest && len(layerKeyChanges(vendorLayer.Metadata, vendorKey)) == 0
+
+				vendorLayer, err = provideVendor(context, vendorLayer, vendorKey, reuse, vendorPruner, pruneOptions, vendorSBOM, logger)
+				if err != nil {
+					return packit.BuildResult{}, err
+				}
+
+				vendorLayer.Launch = vendorLaunch
+				vendorLayer.Build = vendorBuild
+				layers = append(layers, vendorLayer)
+			}
+
+			if migrate != "" {
+				layers, err = migrateToModules(context, moduleMigrator, importPath, cacheLayer.Path, migrate, layers, logger)
+				if err != nil {
+					return packit.BuildResult{}, err
+				}
+			}
+
+			if vendorModules {
+				layers, err = synthesizeVendorModules(context, vendorModulesGenerator, importPath, layers, logger)
+				if err != nil {
+					return packit.BuildResult{}, err
+				}
+			}
+
+			cacheLayer.Cache = true
+			cacheLayer.Metadata = map[string]interface{}{
+				LockDigestKey: ensuredDigest,
+			}
+
+			layers = append(layers, cacheLayer, gopathLayer)
+		} else if build && cacheExported {
+			// Without a Gopkg.toml the cache layer is only kept for the later
+			// buildpacks that DEPCACHEDIR points them to.
+			cacheLayer, err := context.Layers.Get(DepCache)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			err = os.MkdirAll(cacheLayer.Path, os.ModePerm)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			cacheLayer.Cache = true
+			layers = append(layers, cacheLayer)
+		}
+
+		// Without a Gopkg.toml dep never runs, but the Gopkg.lock still
+		// describes the packages in a checked-in vendor directory.
+		if !exists && locked {
+			vendorSBOM, err := generateVendorSBOM(context, vendorSBOMGenerator, clock, logger)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			logger.FormattingSBOM(context.BuildpackInfo.SBOMFormats...)
+			launchMetadata.SBOM, err = vendorSBOM.InFormats(context.BuildpackInfo.SBOMFormats...)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+		}
+
+		// Licenses and vulnerabilities are checked last so that they cover the
+		// vendor directory and Gopkg.lock that dep ensure has just written.
+		vendored, err = fs.Exists(filepath.Join(context.WorkingDir, "vendor"))
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		if vendored {
+			err = inventoryLicenses(context, licenseInventory, denyList, logger)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+		}
+
+		locked, err = fs.Exists(filepath.Join(context.WorkingDir, GopkgLock))
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		if locked {
+			err = scanVulnerabilities(context, vulnerabilityScanner, threshold, logger)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
 		}
 
 		return packit.BuildResult{
-			Layers: []packit.Layer{depLayer},
+			Layers: layers,
 			Build:  buildMetadata,
 			Launch: launchMetadata,
 		}, nil
 	}
 }
+
+// layerKeyField is a metadata entry that a cached layer must match to be
+// reused.
+type layerKeyField struct {
+	Key   string
+	Value string
+}
+
+// layerKeyChanges describes every field of the key that does not match the
+// metadata of a cached layer, in the order of the key.
+func layerKeyChanges(metadata map[string]interface{}, key []layerKeyField) []string {
+	var changes []string
+	for _, field := range key {
+		cached, ok := metadata[field.Key].(string)
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("%s was not recorded", field.Key))
+		case cached != field.Value:
+			changes = append(changes, fmt.Sprintf("%s changed from %q to %q", field.Key, cached, field.Value))
+		}
+	}
+
+	return changes
+}
+
+// goTargets returns the install packages of the dep plan entries as targets
+// relative to the app. Packages below the import path of the app are turned
+// into relative paths, as BP_GO_TARGETS does not accept import paths. The
+// packages that are neither relative nor part of the app cannot be targets,
+// so they are returned separately.
+func goTargets(entries []packit.BuildpackPlanEntry, importPath string) ([]string, []string) {
+	var targets, ignored []string
+	for _, entry := range entries {
+		if entry.Name != Dep {
+			continue
+		}
+
+		var install []string
+		switch value := entry.Metadata["install"].(type) {
+		case []string:
+			install = value
+		case []interface{}:
+			for _, v := range value {
+				if s, ok := v.(string); ok {
+					install = append(install, s)
+				}
+			}
+		}
+
+		for _, pkg := range install {
+			switch {
+			case pkg == importPath:
+				pkg = "."
+			case strings.HasPrefix(pkg, importPath+"/"):
+				pkg = "./" + strings.TrimPrefix(pkg, importPath+"/")
+			case pkg == "." || strings.HasPrefix(pkg, "./"):
+				// A relative package already is a target.
+			default:
+				if !contains(ignored, pkg) {
+					ignored = append(ignored, pkg)
+				}
+				continue
+			}
+
+			if !contains(targets, pkg) {
+				targets = append(targets, pkg)
+			}
+		}
+	}
+
+	return targets, ignored
+}
+
+// generateVendorSBOM generates the SBOM of the vendored packages of the app
+// from its Gopkg.lock. The vendored packages are part of the application, so
+// they are described in the launch SBOM rather than in the SBOM of a layer.
+func generateVendorSBOM(context packit.BuildContext, generator VendorSBOMGenerator, clock chronos.Clock, logger scribe.Emitter) (sbom.SBOM, error) {
+	logger.GeneratingSBOM(context.WorkingDir)
+
+	var vendorSBOM sbom.SBOM
+	duration, err := clock.Measure(func() error {
+		var err error
+		vendorSBOM, err = generator.Generate(context.WorkingDir)
+		return err
+	})
+	if err != nil {
+		return sbom.SBOM{}, err
+	}
+
+	logger.Action("Completed in %s", duration.Round(time.Millisecond))
+	logger.Break()
+
+	return vendorSBOM, nil
+}
+
+// restoreVendor seeds the vendor directory of the app from a dep-vendor layer
+// that was cached for the same Gopkg.lock without pruning, so that dep
+// ensure only has to bring it up to date. A vendor directory that is part of
+// the app is never replaced.
+func restoreVendor(workingDir string, layer packit.Layer, key []layerKeyField, logger scribe.Emitter) error {
+	if len(layerKeyChanges(layer.Metadata, key)) > 0 {
+		return nil
+	}
+
+	vendored, err := fs.Exists(filepath.Join(workingDir, "vendor"))
+	if err != nil {
+		return err
+	}
+
+	cached, err := fs.Exists(filepath.Join(layer.Path, "vendor"))
+	if err != nil {
+		return err
+	}
+
+	if vendored || !cached {
+		return nil
+	}
+
+	err = fs.Copy(filepath.Join(layer.Path, "vendor"), filepath.Join(workingDir, "vendor"))
+	if err != nil {
+		return fmt.Errorf("failed to restore vendor: %w", err)
+	}
+
+	logger.Subprocess("Restored vendor from cached layer %s", layer.Path)
+
+	return nil
+}
+
+// provideVendor copies the vendor directory of the app into the dep-vendor
+// layer, so that the buildpacks requiring it get a vendor tree with an SBOM
+// of its own that is cached for the next build. The prune options are only
+// applied to the copy, so the vendor directory of the app keeps matching its
+// Gopkg.lock. When reuse is set, a cached vendor tree is kept as it is.
+func provideVendor(context packit.BuildContext, layer packit.Layer, key []layerKeyField, reuse bool, pruner VendorPruner, pruneOptions []string, vendorSBOM sbom.SBOM, logger scribe.Emitter) (packit.Layer, error) {
+	logger.Process("Providing dep-vendor")
+
+	layerVendorDir := filepath.Join(layer.Path, "vendor")
+	cached, err := fs.Exists(layerVendorDir)
+	if err != nil {
+		return packit.Layer{}, err
+	}
+
+	if reuse && cached {
+		logger.Subprocess("Reusing cached layer %s", layer.Path)
+	} else {
+		layer, err = layer.Reset()
+		if err != nil {
+			return packit.Layer{}, err
+		}
+
+		vendorDir := filepath.Join(context.WorkingDir, "vendor")
+		vendored, err := fs.Exists(vendorDir)
+		if err != nil {
+			return packit.Layer{}, err
+		}
+
+		if vendored {
+			err = fs.Copy(vendorDir, layerVendorDir)
+		} else {
+			err = os.MkdirAll(layerVendorDir, os.ModePerm)
+		}
+		if err != nil {
+			return packit.Layer{}, fmt.Errorf("failed to provide dep-vendor: %w", err)
+		}
+
+		logger.Subprocess("Copied vendor into %s", layer.Path)
+
+		if len(pruneOptions) > 0 {
+			logger.Subprocess("Pruning vendor: %s", strings.Join(pruneOptions, ", "))
+
+			err = pruner.Prune(context.WorkingDir, layerVendorDir, pruneOptions)
+			if err != nil {
+				return packit.Layer{}, err
+			}
+		}
+	}
+
+	layer.SBOM, err = vendorSBOM.InFormats(context.BuildpackInfo.SBOMFormats...)
+	if err != nil {
+		return packit.Layer{}, err
+	}
+
+	layer.Cache = true
+	layer.Metadata = map[string]interface{}{}
+	for _, field := range key {
+		layer.Metadata[field.Key] = field.Value
+	}
+
+	logger.Break()
+
+	return layer, nil
+}
+
+// inventoryLicenses logs the licenses of the vendored projects of the app and
+// fails when any of them is on the deny list.
+func inventoryLicenses(context packit.BuildContext, inventory LicenseInventory, denyList []string, logger scribe.Emitter) error {
+	logger.Process("Collecting licenses of vendored projects")
+
+	projects, err := inventory.Collect(context.WorkingDir)
+	if err != nil {
+		return err
+	}
+
+	var denied int
+	for _, project := range projects {
+		switch {
+		case len(project.Licenses) == 0 && len(project.Unclassified) == 0:
+			logger.Subprocess("%s: no license found", project.Project)
+		case len(project.Licenses) == 0:
+			logger.Subprocess("%s: unclassified", project.Project)
+		default:
+			logger.Subprocess("%s: %s", project.Project, strings.Join(project.Licenses, ", "))
+		}
+
+		for _, file := range project.Unclassified {
+			logger.Action("Could not classify %s", file)
+		}
+
+		if licenses := licenseDenied(project.Licenses, denyList); len(licenses) > 0 {
+			logger.Action("Denied by BP_DEP_LICENSE_DENY_LIST: %s", strings.Join(licenses, ", "))
+			denied++
+		}
+	}
+	logger.Break()
+
+	if denied > 0 {
+		return fmt.Errorf("found %d vendored project(s) with a license in BP_DEP_LICENSE_DENY_LIST", denied)
+	}
+
+	return nil
+}
+
+// scanVulnerabilities logs the known vulnerabilities of the locked projects of
+// the app and fails when any of them is at or above the threshold.
+func scanVulnerabilities(context packit.BuildContext, scanner VulnerabilityScanner, threshold Severity, logger scribe.Emitter) error {
+	scan, err := scanner.Scan(context.WorkingDir, context.Platform.Path)
+	if err != nil {
+		return err
+	}
+
+	if scan.Database == "" {
+		if threshold != SeverityUnknown {
+			return fmt.Errorf("BP_DEP_VULNERABILITY_THRESHOLD is set, but no OSV database is configured in BP_DEP_OSV_DATABASE or an 'osv-database' binding")
+		}
+
+		return nil
+	}
+
+	logger.Process("Scanning Gopkg.lock for known vulnerabilities")
+	logger.Subprocess("Using OSV database from %s", scan.Database)
+
+	if len(scan.Findings) == 0 {
+		logger.Action("No known vulnerabilities found")
+	} else {
+		var table strings.Builder
+		writer := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "PROJECT\tVERSION\tID\tSEVERITY\tFIXED")
+		for _, finding := range scan.Findings {
+			fixed := finding.Fixed
+			if fixed == "" {
+				fixed = "-"
+			}
+
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", finding.Project, finding.Version, finding.ID, finding.Severity, fixed)
+		}
+		writer.Flush()
+
+		for _, line := range strings.Split(strings.TrimSuffix(table.String(), "\n"), "\n") {
+			logger.Action(line)
+		}
+	}
+
+	if len(scan.Unverifiable) > 0 {
+		logger.Subprocess("The following projects are not locked to a version and could not be checked:")
+		for _, project := range scan.Unverifiable {
+			logger.Action(project)
+		}
+	}
+	logger.Break()
+
+	if threshold == SeverityUnknown {
+		return nil
+	}
+
+	var count int
+	for _, finding := range scan.Findings {
+		if finding.Severity >= threshold {
+			count++
+		}
+	}
+
+	if count == 1 {
+		return fmt.Errorf("found 1 known vulnerability at or above %s severity in Gopkg.lock", threshold)
+	}
+
+	if count > 1 {
+		return fmt.Errorf("found %d known vulnerabilities at or above %s severity in Gopkg.lock", count, threshold)
+	}
+
+	return nil
+}
+
+// migrateToModules writes the Go modules equivalent of the Gopkg files of the
+// app into a build layer in report mode, or into the app in apply mode.
+func migrateToModules(context packit.BuildContext, migrator ModuleMigrator, importPath, cacheDir, mode string, layers []packit.Layer, logger scribe.Emitter) ([]packit.Layer, error) {
+	logger.Process("Migrating to Go modules (%s)", mode)
+
+	migration, err := migrator.Migrate(context.WorkingDir, cacheDir, importPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(migration.Untranslatable) > 0 {
+		logger.Subprocess("The following could not be translated:")
+		for _, item := range migration.Untranslatable {
+			logger.Action(item)
+		}
+	}
+
+	if mode == "report" {
+		migrationLayer, err := context.Layers.Get(ModulesMigration)
+		if err != nil {
+			return nil, err
+		}
+
+		migrationLayer, err = migrationLayer.Reset()
+		if err != nil {
+			return nil, err
+		}
+
+		err = os.WriteFile(filepath.Join(migrationLayer.Path, "go.mod"), []byte(migration.GoMod), 0644)
+		if err != nil {
+			return nil, err
+		}
+
+		// The checksums of the modules can only be calculated from the modules
+		// themselves, so the go.sum is left for `go mod tidy` to fill in.
+		err = os.WriteFile(filepath.Join(migrationLayer.Path, "go.sum"), nil, 0644)
+		if err != nil {
+			return nil, err
+		}
+
+		var report strings.Builder
+		for _, item := range migration.Untranslatable {
+			fmt.Fprintf(&report, "%s\n", item)
+		}
+
+		err = os.WriteFile(filepath.Join(migrationLayer.Path, "untranslatable.txt"), []byte(report.String()), 0644)
+		if err != nil {
+			return nil, err
+		}
+
+		migrationLayer.Build = true
+
+		logger.Subprocess("go.mod:")
+		for _, line := range strings.Split(strings.TrimSuffix(migration.GoMod, "\n"), "\n") {
+			logger.Action(line)
+		}
+		logger.Subprocess("Wrote go.mod and go.sum to %s", migrationLayer.Path)
+		logger.Break()
+
+		return append(layers, migrationLayer), nil
+	}
+
+	if len(migration.Unresolvable) > 0 {
+		return nil, fmt.Errorf("failed to migrate to Go modules: no version that Go can resolve for %s; lock them to a semantic version, or run with BP_DEP_MIGRATE=report to see the go.mod", strings.Join(migration.Unresolvable, ", "))
+	}
+
+	goModPath := filepath.Join(context.WorkingDir, "go.mod")
+	exists, err := fs.Exists(goModPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if exists {
+		return nil, fmt.Errorf("failed to migrate to Go modules: %s already exists", goModPath)
+	}
+
+	err = os.WriteFile(goModPath, []byte(migration.GoMod), 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Subprocess("Wrote %s", goModPath)
+	logger.Break()
+
+	return layers, nil
+}
+
+// synthesizeVendorModules writes a go.mod and vendor/modules.txt for the
+// vendor directory of the app into a build layer and copies them into the
+// app, so that later buildpacks can build it with -mod=vendor.
+func synthesizeVendorModules(context packit.BuildContext, generator VendorModulesGenerator, importPath string, layers []packit.Layer, logger scribe.Emitter) ([]packit.Layer, error) {
+	logger.Process("Generating go.mod and vendor/modules.txt")
+
+	goModPath := filepath.Join(context.WorkingDir, "go.mod")
+	exists, err := fs.Exists(goModPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if exists {
+		return nil, fmt.Errorf("failed to generate vendor/modules.txt: %s already exists", goModPath)
+	}
+
+	modules, err := generator.Generate(context.WorkingDir, importPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(modules.Unexpressible) > 0 {
+		logger.Subprocess("The following dep projects cannot be expressed as module versions:")
+		for _, item := range modules.Unexpressible {
+			logger.Action(item)
+		}
+	}
+
+	modulesLayer, err := context.Layers.Get(VendorModulesLayer)
+	if err != nil {
+		return nil, err
+	}
+
+	modulesLayer, err = modulesLayer.Reset()
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.WriteFile(filepath.Join(modulesLayer.Path, "go.mod"), []byte(modules.GoMod), 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.WriteFile(filepath.Join(modulesLayer.Path, "modules.txt"), []byte(modules.ModulesTxt), 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	err = fs.Copy(filepath.Join(modulesLayer.Path, "go.mod"), goModPath)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(filepath.Join(context.WorkingDir, "vendor"), os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+
+	err = fs.Copy(filepath.Join(modulesLayer.Path, "modules.txt"), filepath.Join(context.WorkingDir, "vendor", "modules.txt"))
+	if err != nil {
+		return nil, err
+	}
+
+	modulesLayer.Build = true
+	modulesLayer.BuildEnv.Override("GO111MODULE", "on")
+	modulesLayer.BuildEnv.Default("GOFLAGS", "-mod=vendor")
+
+	logger.Subprocess("Wrote go.mod and vendor/modules.txt to %s", context.WorkingDir)
+	logger.Break()
+
+	return append(layers, modulesLayer), nil
+}
+
+func gopkgLockDigest(workingDir string) (string, error) {
+	path := filepath.Join(workingDir, GopkgLock)
+
+	exists, err := fs.Exists(path)
+	if err != nil {
+		return "", err
+	}
+
+	if !exists {
+		return "", nil
+	}
+
+	return fs.NewChecksumCalculator().Sum(path)
+}
+
+// versionConstraints returns the distinct version constraints requested by the
+// given entries in the order that they are given.
+func versionConstraints(entries []packit.BuildpackPlanEntry) []string {
+	var constraints []string
+	for _, entry := range entries {
+		version, ok := entry.Metadata["version"].(string)
+		if !ok || version == "" || version == "default" {
+			continue
+		}
+
+		var duplicate bool
+		for _, constraint := range constraints {
+			if constraint == version {
+				duplicate = true
+				break
+			}
+		}
+
+		if !duplicate {
+			constraints = append(constraints, version)
+		}
+	}
+
+	return constraints
+}
+
+// redactURI removes any credentials from a URI so that it can be logged.
+func redactURI(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+
+	return u.Redacted()
+}
+
+// hasEnv reports whether env, a list of KEY=value pairs, sets name.
+func hasEnv(env []string, name string) bool {
+	for _, variable := range env {
+		if strings.HasPrefix(variable, name+"=") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseBoolEnv returns the boolean value of the environment variable with the
+// given name, or false when it is unset.
+func parseBoolEnv(name string) (bool, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return false, nil
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+
+	return enabled, nil
+}
@@ -0,0 +1,44 @@
+package dep
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+type BuildpackYMLParser struct{}
+
+func NewBuildpackYMLParser() BuildpackYMLParser {
+	return BuildpackYMLParser{}
+}
+
+// ParseVersion returns the dep version requested in a buildpack.yml file. It
+// returns an empty string when the file does not exist or does not specify a
+// version.
+func (p BuildpackYMLParser) ParseVersion(path string) (string, error) {
+	var buildpack struct {
+		Dep struct {
+			Version string `yaml:"version"`
+		} `yaml:"dep"`
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("failed to open buildpack.yml: %w", err)
+	}
+	defer file.Close()
+
+	err = yaml.NewDecoder(file).Decode(&buildpack)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to parse buildpack.yml: %w", err)
+	}
+
+	return buildpack.Dep.Version, nil
+}
@@ -0,0 +1,89 @@
+package dep_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/dep"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBuildpackYMLParser(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+		parser     dep.BuildpackYMLParser
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = os.MkdirTemp("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+
+		parser = dep.NewBuildpackYMLParser()
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	context("ParseVersion", func() {
+		it.Before(func() {
+			err := os.WriteFile(filepath.Join(workingDir, "buildpack.yml"), []byte(`---
+dep:
+  version: 0.5.*
+`), 0600)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it("parses the dep version from a buildpack.yml file", func() {
+			version, err := parser.ParseVersion(filepath.Join(workingDir, "buildpack.yml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal("0.5.*"))
+		})
+
+		context("when the buildpack.yml file does not exist", func() {
+			it("returns an empty version", func() {
+				version, err := parser.ParseVersion(filepath.Join(workingDir, "missing.yml"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(version).To(BeEmpty())
+			})
+		})
+
+		context("when the buildpack.yml file is empty", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "buildpack.yml"), nil, 0600)).To(Succeed())
+			})
+
+			it("returns an empty version", func() {
+				version, err := parser.ParseVersion(filepath.Join(workingDir, "buildpack.yml"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(version).To(BeEmpty())
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the buildpack.yml file cannot be opened", func() {
+				it("returns an error", func() {
+					_, err := parser.ParseVersion(filepath.Join(workingDir, "buildpack.yml", "not-a-dir"))
+					Expect(err).To(MatchError(ContainSubstring("failed to open buildpack.yml")))
+				})
+			})
+
+			context("when the buildpack.yml file cannot be parsed", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "buildpack.yml"), []byte("%%%"), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := parser.ParseVersion(filepath.Join(workingDir, "buildpack.yml"))
+					Expect(err).To(MatchError(ContainSubstring("failed to parse buildpack.yml")))
+				})
+			})
+		})
+	})
+}
@@ -1,6 +1,25 @@
 package dep
 
 const (
-	Dep                = "dep"
-	DependencyCacheKey = "dependency-sha"
+	Dep                  = "dep"
+	DepVendor            = "dep-vendor"
+	DepCache             = "dep-cache"
+	Gopath               = "gopath"
+	GitConfig            = "git-config"
+	ModulesMigration     = "modules-migration"
+	VendorModulesLayer   = "vendor-modules"
+	DependencyCacheKey   = "dependency-sha"
+	DependencyArchKey    = "dependency-arch"
+	DependencyVersionKey = "dependency-version"
+	DependencyURIKey     = "dependency-uri"
+	StackKey             = "stack"
+	BuildpackVersionKey  = "buildpack-version"
+	LayerManifestKey     = "files"
+	EnvironmentKey       = "environment"
+	LockDigestKey        = "gopkg-lock-sha"
+	PruneKey             = "prune"
+
+	GopkgToml      = "Gopkg.toml"
+	GopkgLock      = "Gopkg.lock"
+	DepReceiptFile = "dep.toml"
 )
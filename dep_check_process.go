@@ -0,0 +1,130 @@
+package dep
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/paketo-buildpacks/packit/v2/pexec"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+// checkFinding is a single mismatch reported by `dep check`.
+type checkFinding struct {
+	// Section is the part of the project that is out of sync, for example
+	// "Gopkg.lock" or "vendor".
+	Section string
+	Project string
+	Problem string
+}
+
+type DepCheckProcess struct {
+	executable Executable
+	logger     scribe.Emitter
+}
+
+func NewDepCheckProcess(executable Executable, logger scribe.Emitter) DepCheckProcess {
+	return DepCheckProcess{
+		executable: executable,
+		logger:     logger,
+	}
+}
+
+// Execute runs `dep check` against the app at importPath in the given GOPATH
+// using the dep executable found in binPath. It verifies that the Gopkg.lock
+// satisfies the imports and constraints of the app. The vendor directory is
+// skipped, as it is verified by the VendorVerifier. Any mismatch is logged per
+// project and returned as an error.
+func (p DepCheckProcess) Execute(gopath, importPath, binPath string) error {
+	appPath := filepath.Join(gopath, "src", filepath.FromSlash(importPath))
+
+	buffer := bytes.NewBuffer(nil)
+	err := p.executable.Execute(pexec.Execution{
+		Args:   []string{"check", "-skip-vendor"},
+		Env:    depEnvironment(gopath, appPath, binPath),
+		Dir:    appPath,
+		Stdout: buffer,
+		Stderr: buffer,
+	})
+	if err == nil {
+		p.logger.Action("Gopkg.lock is in sync")
+		return nil
+	}
+
+	findings := parseCheckOutput(buffer.String())
+	if len(findings) == 0 {
+		p.logger.Detail(buffer.String())
+		return fmt.Errorf("failed to execute 'dep check': %w", err)
+	}
+
+	var projects []string
+	var section, project string
+	for _, finding := range findings {
+		if finding.Section != section {
+			section, project = finding.Section, ""
+			p.logger.Action("%s is out of sync:", section)
+		}
+
+		if finding.Project != project {
+			project = finding.Project
+			p.logger.Detail(project)
+
+			if !contains(projects, project) {
+				projects = append(projects, project)
+			}
+		}
+
+		p.logger.Subdetail(finding.Problem)
+	}
+
+	return fmt.Errorf("dep check found %d out of sync project(s), run 'dep ensure' and commit the result", len(projects))
+}
+
+// parseCheckOutput parses the report that `dep check` prints, which consists
+// of sections like:
+//
+//	# Gopkg.lock is out of sync:
+//	github.com/some-org/some-repo: imported or required, but missing from Gopkg.lock's input-imports
+//	github.com/some-org/other-repo@v1.0.0: not allowed by constraint ^2.0.0
+//
+//	# vendor is out of sync:
+//	github.com/some-org/some-repo: hash of vendored tree not equal to digest in Gopkg.lock
+func parseCheckOutput(output string) []checkFinding {
+	var findings []checkFinding
+	var section string
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, "#") {
+			section = strings.TrimSuffix(strings.TrimSpace(strings.TrimPrefix(line, "#")), ":")
+			section = strings.TrimSuffix(section, " is out of sync")
+			continue
+		}
+
+		if section == "" || line == "" {
+			continue
+		}
+
+		project, problem, found := strings.Cut(line, ": ")
+		if !found {
+			continue
+		}
+
+		if name, version, ok := strings.Cut(project, "@"); ok {
+			project = name
+			problem = fmt.Sprintf("%s (locked at %s)", problem, version)
+		}
+
+		findings = append(findings, checkFinding{
+			Section: section,
+			Project: project,
+			Problem: problem,
+		})
+	}
+
+	return findings
+}
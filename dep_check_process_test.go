@@ -0,0 +1,108 @@
+package dep_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/dep"
+	"github.com/paketo-buildpacks/dep/fakes"
+	"github.com/paketo-buildpacks/packit/v2/pexec"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDepCheckProcess(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		buffer     *bytes.Buffer
+		executable *fakes.Executable
+		executions []pexec.Execution
+
+		process dep.DepCheckProcess
+	)
+
+	it.Before(func() {
+		executions = []pexec.Execution{}
+		executable = &fakes.Executable{}
+		executable.ExecuteCall.Stub = func(execution pexec.Execution) error {
+			executions = append(executions, execution)
+			return nil
+		}
+
+		buffer = bytes.NewBuffer(nil)
+		process = dep.NewDepCheckProcess(executable, scribe.NewEmitter(buffer))
+	})
+
+	it("runs dep check at the import path of the app inside of the GOPATH", func() {
+		err := process.Execute("some-gopath", "github.com/some-org/some-app", "some-bin-path")
+		Expect(err).NotTo(HaveOccurred())
+
+		appPath := filepath.Join("some-gopath", "src", "github.com", "some-org", "some-app")
+
+		Expect(executions).To(HaveLen(1))
+		Expect(executions[0].Args).To(Equal([]string{"check", "-skip-vendor"}))
+		Expect(executions[0].Dir).To(Equal(appPath))
+		Expect(executions[0].Env).To(ContainElement("GOPATH=some-gopath"))
+		Expect(executions[0].Env).To(ContainElement("GO111MODULE=off"))
+		Expect(executions[0].Env).To(ContainElement(fmt.Sprintf("PWD=%s", appPath)))
+		Expect(executions[0].Env).To(ContainElement(MatchRegexp(`^PATH=some-bin-path:`)))
+
+		Expect(buffer.String()).To(ContainSubstring("Gopkg.lock is in sync"))
+	})
+
+	context("when dep check reports projects that are out of sync", func() {
+		it.Before(func() {
+			executable.ExecuteCall.Stub = func(execution pexec.Execution) error {
+				fmt.Fprintln(execution.Stdout, `# Gopkg.lock is out of sync:
+github.com/some-org/some-repo: imported or required, but missing from Gopkg.lock's input-imports
+github.com/some-org/other-repo@v1.0.0: not allowed by constraint ^2.0.0
+
+# vendor is out of sync:
+github.com/some-org/other-repo: hash of vendored tree not equal to digest in Gopkg.lock
+github.com/some-org/unused-repo: unused project`)
+				return errors.New("exit status 1")
+			}
+		})
+
+		it("logs a report per project and returns an error", func() {
+			err := process.Execute("some-gopath", "app", "some-bin-path")
+			Expect(err).To(MatchError("dep check found 3 out of sync project(s), run 'dep ensure' and commit the result"))
+
+			Expect(buffer.String()).To(ContainSubstring(`      Gopkg.lock is out of sync:
+        github.com/some-org/some-repo
+          imported or required, but missing from Gopkg.lock's input-imports
+        github.com/some-org/other-repo
+          not allowed by constraint ^2.0.0 (locked at v1.0.0)
+      vendor is out of sync:
+        github.com/some-org/other-repo
+          hash of vendored tree not equal to digest in Gopkg.lock
+        github.com/some-org/unused-repo
+          unused project
+`))
+		})
+	})
+
+	context("failure cases", func() {
+		context("when dep check fails without a report", func() {
+			it.Before(func() {
+				executable.ExecuteCall.Stub = func(execution pexec.Execution) error {
+					fmt.Fprintln(execution.Stderr, "could not find project Gopkg.toml")
+					return errors.New("exit status 1")
+				}
+			})
+
+			it("returns an error and logs the output", func() {
+				err := process.Execute("some-gopath", "app", "some-bin-path")
+				Expect(err).To(MatchError("failed to execute 'dep check': exit status 1"))
+
+				Expect(buffer.String()).To(ContainSubstring("could not find project Gopkg.toml"))
+			})
+		})
+	})
+}
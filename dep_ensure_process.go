@@ -0,0 +1,67 @@
+package dep
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/paketo-buildpacks/packit/v2/pexec"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+//go:generate faux --interface Executable --output fakes/executable.go
+type Executable interface {
+	Execute(pexec.Execution) error
+}
+
+type DepEnsureProcess struct {
+	executable Executable
+	logger     scribe.Emitter
+}
+
+func NewDepEnsureProcess(executable Executable, logger scribe.Emitter) DepEnsureProcess {
+	return DepEnsureProcess{
+		executable: executable,
+		logger:     logger,
+	}
+}
+
+// Execute runs `dep ensure` against the app at importPath in the given GOPATH
+// using the dep executable found in binPath and with cachePath as the
+// DEPCACHEDIR so that fetched sources persist between builds. The app is
+// expected to be linked into the GOPATH, so that the vendor directory and
+// Gopkg.lock are written straight into the app. The given env is added to the
+// environment of dep, for example to pass credentials for private
+// repositories.
+func (p DepEnsureProcess) Execute(gopath, importPath, binPath, cachePath string, env []string) error {
+	appPath := filepath.Join(gopath, "src", filepath.FromSlash(importPath))
+
+	buffer := bytes.NewBuffer(nil)
+	err := p.executable.Execute(pexec.Execution{
+		Args:   []string{"ensure"},
+		Env:    append(append(depEnvironment(gopath, appPath, binPath), fmt.Sprintf("DEPCACHEDIR=%s", cachePath)), env...),
+		Dir:    appPath,
+		Stdout: buffer,
+		Stderr: buffer,
+	})
+	if err != nil {
+		p.logger.Detail(buffer.String())
+		return fmt.Errorf("failed to execute 'dep ensure': %w", err)
+	}
+
+	return nil
+}
+
+// depEnvironment returns the environment that dep runs in for the app at
+// appPath inside of gopath, with the dep executable found in binPath.
+func depEnvironment(gopath, appPath, binPath string) []string {
+	return append(os.Environ(),
+		fmt.Sprintf("GOPATH=%s", gopath),
+		"GO111MODULE=off",
+		fmt.Sprintf("PATH=%s%c%s", binPath, os.PathListSeparator, os.Getenv("PATH")),
+		// dep finds the project root from the working directory, which would
+		// otherwise resolve the link to the app and end up outside of the GOPATH.
+		fmt.Sprintf("PWD=%s", appPath),
+	)
+}
@@ -0,0 +1,130 @@
+package dep_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/dep"
+	"github.com/paketo-buildpacks/dep/fakes"
+	"github.com/paketo-buildpacks/packit/v2/pexec"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDepEnsureProcess(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workspace  string
+		gopath     string
+		buffer     *bytes.Buffer
+		executable *fakes.Executable
+		executions []pexec.Execution
+
+		process dep.DepEnsureProcess
+	)
+
+	it.Before(func() {
+		var err error
+		workspace, err = os.MkdirTemp("", "workspace")
+		Expect(err).NotTo(HaveOccurred())
+
+		gopath, err = os.MkdirTemp("", "gopath")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.WriteFile(filepath.Join(workspace, "Gopkg.toml"), []byte("some-manifest"), 0600)).To(Succeed())
+
+		appPath := filepath.Join(gopath, "src", "github.com", "some-org", "some-app")
+		Expect(os.MkdirAll(filepath.Dir(appPath), os.ModePerm)).To(Succeed())
+		Expect(os.Symlink(workspace, appPath)).To(Succeed())
+
+		executions = []pexec.Execution{}
+		executable = &fakes.Executable{}
+		executable.ExecuteCall.Stub = func(execution pexec.Execution) error {
+			executions = append(executions, execution)
+
+			contents, err := os.ReadFile(filepath.Join(execution.Dir, "Gopkg.toml"))
+			if err != nil {
+				return err
+			}
+
+			if string(contents) != "some-manifest" {
+				return fmt.Errorf("unexpected manifest: %s", contents)
+			}
+
+			err = os.MkdirAll(filepath.Join(execution.Dir, "vendor", "github.com", "some-org", "some-repo"), os.ModePerm)
+			if err != nil {
+				return err
+			}
+
+			return os.WriteFile(filepath.Join(execution.Dir, "Gopkg.lock"), []byte("some-lock"), 0600)
+		}
+
+		buffer = bytes.NewBuffer(nil)
+		process = dep.NewDepEnsureProcess(executable, scribe.NewEmitter(buffer))
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workspace)).To(Succeed())
+		Expect(os.RemoveAll(gopath)).To(Succeed())
+	})
+
+	it("runs dep ensure at the import path of the app inside of the GOPATH", func() {
+		err := process.Execute(gopath, "github.com/some-org/some-app", "some-bin-path", "some-cache-path", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		appPath := filepath.Join(gopath, "src", "github.com", "some-org", "some-app")
+
+		Expect(executions).To(HaveLen(1))
+		Expect(executions[0].Args).To(Equal([]string{"ensure"}))
+		Expect(executions[0].Dir).To(Equal(appPath))
+		Expect(executions[0].Env).To(ContainElement(fmt.Sprintf("GOPATH=%s", gopath)))
+		Expect(executions[0].Env).To(ContainElement("GO111MODULE=off"))
+		Expect(executions[0].Env).To(ContainElement(fmt.Sprintf("PWD=%s", appPath)))
+		Expect(executions[0].Env).To(ContainElement(MatchRegexp(`^PATH=some-bin-path:`)))
+		Expect(executions[0].Env).To(ContainElement("DEPCACHEDIR=some-cache-path"))
+
+		Expect(filepath.Join(workspace, "vendor", "github.com", "some-org", "some-repo")).To(BeADirectory())
+
+		contents, err := os.ReadFile(filepath.Join(workspace, "Gopkg.lock"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(contents)).To(Equal("some-lock"))
+	})
+
+	context("when additional environment is given", func() {
+		it("adds it to the environment of dep ensure", func() {
+			err := process.Execute(gopath, "github.com/some-org/some-app", "some-bin-path", "some-cache-path", []string{"HOME=some-home", "GIT_SSH_COMMAND=some-command"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executions).To(HaveLen(1))
+			Expect(executions[0].Env).To(ContainElement("HOME=some-home"))
+			Expect(executions[0].Env).To(ContainElement("GIT_SSH_COMMAND=some-command"))
+		})
+	})
+
+	context("failure cases", func() {
+		context("when dep ensure fails", func() {
+			it.Before(func() {
+				executable.ExecuteCall.Stub = func(execution pexec.Execution) error {
+					fmt.Fprintln(execution.Stdout, "some stdout output")
+					fmt.Fprintln(execution.Stderr, "some stderr output")
+					return errors.New("exit status 1")
+				}
+			})
+
+			it("returns an error and logs the output", func() {
+				err := process.Execute(gopath, "github.com/some-org/some-app", "some-bin-path", "some-cache-path", nil)
+				Expect(err).To(MatchError("failed to execute 'dep ensure': exit status 1"))
+
+				Expect(buffer.String()).To(ContainSubstring("some stdout output"))
+				Expect(buffer.String()).To(ContainSubstring("some stderr output"))
+			})
+		})
+	})
+}
@@ -0,0 +1,93 @@
+package dep
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	"golang.org/x/sys/unix"
+)
+
+// exportDepEnvironment sets the environment that later buildpacks run dep in
+// on the given layer:
+//
+//   - DEP_HOME is the dep layer
+//   - DEPCACHEDIR is the cache layer at cachePath, at build time only
+//   - DEPNOLOCK is set when DEPCACHEDIR is on a read-only filesystem, where
+//     dep cannot lock it
+//
+// Each variable is a default that the platform can override with its
+// BP_DEP_HOME, BP_DEP_CACHEDIR or BP_DEP_NOLOCK counterpart. It reports
+// whether DEPCACHEDIR is the cache layer.
+func exportDepEnvironment(layer *packit.Layer, cachePath string) (bool, error) {
+	// The dep layer exports nothing else, so this replaces any environment
+	// the layer already has.
+	layer.SharedEnv = packit.Environment{}
+	layer.BuildEnv = packit.Environment{}
+
+	if home, ok := os.LookupEnv("BP_DEP_HOME"); ok {
+		layer.SharedEnv.Override("DEP_HOME", home)
+	} else {
+		layer.SharedEnv.Default("DEP_HOME", layer.Path)
+	}
+
+	cacheDir := cachePath
+	if dir, ok := os.LookupEnv("BP_DEP_CACHEDIR"); ok {
+		cacheDir = dir
+		layer.BuildEnv.Override("DEPCACHEDIR", dir)
+	} else {
+		layer.BuildEnv.Default("DEPCACHEDIR", cachePath)
+	}
+
+	if noLock, ok := os.LookupEnv("BP_DEP_NOLOCK"); ok {
+		layer.SharedEnv.Override("DEPNOLOCK", noLock)
+	} else {
+		readOnly, err := readOnlyFilesystem(cacheDir)
+		if err != nil {
+			return false, err
+		}
+
+		if readOnly {
+			layer.SharedEnv.Default("DEPNOLOCK", "1")
+		}
+	}
+
+	return cacheDir == cachePath, nil
+}
+
+// readOnlyFilesystem reports whether path, or its closest existing parent
+// when path does not exist yet, is on a read-only filesystem.
+func readOnlyFilesystem(path string) (bool, error) {
+	for {
+		var stat unix.Statfs_t
+		err := unix.Statfs(path, &stat)
+		if err == nil {
+			return stat.Flags&unix.ST_RDONLY != 0, nil
+		}
+
+		parent := filepath.Dir(path)
+		if !errors.Is(err, os.ErrNotExist) || parent == path {
+			return false, err
+		}
+
+		path = parent
+	}
+}
+
+// environmentKey describes the environment of a layer on a single line, so
+// that it can be recorded in the layer metadata.
+func environmentKey(layer packit.Layer) string {
+	var entries []string
+	for _, env := range []packit.Environment{layer.SharedEnv, layer.BuildEnv} {
+		for name, value := range env {
+			entries = append(entries, fmt.Sprintf("%s=%s", name, value))
+		}
+	}
+	sort.Strings(entries)
+
+	return strings.Join(entries, " ")
+}
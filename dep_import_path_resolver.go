@@ -0,0 +1,153 @@
+package dep
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultImportPath is the import path that the app is placed at in the
+// GOPATH when no other import path can be determined.
+const DefaultImportPath = "app"
+
+type DepImportPathResolver struct {
+	gopkgTomlParser GopkgParser
+}
+
+func NewDepImportPathResolver(gopkgTomlParser GopkgParser) DepImportPathResolver {
+	return DepImportPathResolver{
+		gopkgTomlParser: gopkgTomlParser,
+	}
+}
+
+// Resolve returns the import path of the app in workingDir and where it was
+// found. The import path is taken from BP_GO_IMPORT_PATH, the heroku
+// root-package in Gopkg.toml, or the origin remote in .git/config, in that
+// order, and is DefaultImportPath when none of them are present.
+func (r DepImportPathResolver) Resolve(workingDir string) (string, string, error) {
+	if importPath, ok := os.LookupEnv("BP_GO_IMPORT_PATH"); ok && importPath != "" {
+		return validImportPath(importPath, "BP_GO_IMPORT_PATH")
+	}
+
+	heroku, err := r.gopkgTomlParser.ParseHerokuMetadata(filepath.Join(workingDir, GopkgToml))
+	if err != nil {
+		return "", "", err
+	}
+
+	if heroku.RootPackage != "" {
+		return validImportPath(heroku.RootPackage, GopkgToml)
+	}
+
+	remote, err := gitRemoteURL(filepath.Join(workingDir, ".git", "config"))
+	if err != nil {
+		return "", "", err
+	}
+
+	if importPath := importPathFromRemote(remote); importPath != "" {
+		return validImportPath(importPath, ".git/config")
+	}
+
+	return DefaultImportPath, "", nil
+}
+
+// validImportPath rejects import paths that would place the app outside of
+// the src directory of the GOPATH or at the src directory itself. Every
+// segment must be a name, so "." and "..", empty, and whitespace-only
+// segments are invalid.
+func validImportPath(importPath, source string) (string, string, error) {
+	importPath = strings.Trim(importPath, "/")
+	for _, segment := range strings.Split(importPath, "/") {
+		if strings.TrimSpace(segment) == "" || segment == "." || segment == ".." {
+			return "", "", fmt.Errorf("invalid import path %q from %s", importPath, source)
+		}
+	}
+
+	return importPath, source, nil
+}
+
+// gitRemoteURL returns the url of the origin remote in the git config file at
+// path, or of the first remote when there is no origin.
+func gitRemoteURL(configPath string) (string, error) {
+	file, err := os.Open(configPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("failed to read git config: %w", err)
+	}
+	defer file.Close()
+
+	var section, first string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			section = line
+			continue
+		}
+
+		if !strings.HasPrefix(section, "[remote ") {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found || strings.TrimSpace(key) != "url" {
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+		if section == `[remote "origin"]` {
+			return value, nil
+		}
+
+		if first == "" {
+			first = value
+		}
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return "", fmt.Errorf("failed to read git config: %w", err)
+	}
+
+	return first, nil
+}
+
+// importPathFromRemote converts a git remote such as
+// "git@github.com:some-org/some-app.git" or
+// "https://github.com/some-org/some-app" into the import path
+// "github.com/some-org/some-app".
+func importPathFromRemote(remote string) string {
+	if remote == "" {
+		return ""
+	}
+
+	var host, repo string
+	if u, err := url.Parse(remote); err == nil && u.Scheme != "" && u.Host != "" {
+		host, repo = u.Hostname(), u.Path
+	} else {
+		// scp-like syntax: [user@]host:path
+		address, p, found := strings.Cut(remote, ":")
+		if !found {
+			return ""
+		}
+
+		if _, h, ok := strings.Cut(address, "@"); ok {
+			address = h
+		}
+
+		host, repo = address, p
+	}
+
+	repo = strings.TrimSuffix(strings.Trim(repo, "/"), ".git")
+	if host == "" || repo == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s/%s", host, repo)
+}
@@ -0,0 +1,199 @@
+package dep_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/dep"
+	"github.com/paketo-buildpacks/dep/fakes"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDepImportPathResolver(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir      string
+		gopkgTomlParser *fakes.GopkgParser
+
+		resolver dep.DepImportPathResolver
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = os.MkdirTemp("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+
+		gopkgTomlParser = &fakes.GopkgParser{}
+
+		resolver = dep.NewDepImportPathResolver(gopkgTomlParser)
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	writeGitConfig := func(config string) {
+		Expect(os.MkdirAll(filepath.Join(workingDir, ".git"), os.ModePerm)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(workingDir, ".git", "config"), []byte(config), 0600)).To(Succeed())
+	}
+
+	it("defaults to the app import path", func() {
+		importPath, source, err := resolver.Resolve(workingDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(importPath).To(Equal("app"))
+		Expect(source).To(BeEmpty())
+
+		Expect(gopkgTomlParser.ParseHerokuMetadataCall.Receives.Path).To(Equal(filepath.Join(workingDir, "Gopkg.toml")))
+	})
+
+	context("when BP_GO_IMPORT_PATH is set", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_GO_IMPORT_PATH", "github.com/some-org/some-env-app/")).To(Succeed())
+			gopkgTomlParser.ParseHerokuMetadataCall.Returns.HerokuMetadata = dep.HerokuMetadata{
+				RootPackage: "github.com/some-org/some-heroku-app",
+			}
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_GO_IMPORT_PATH")).To(Succeed())
+		})
+
+		it("takes priority over the Gopkg.toml", func() {
+			importPath, source, err := resolver.Resolve(workingDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(importPath).To(Equal("github.com/some-org/some-env-app"))
+			Expect(source).To(Equal("BP_GO_IMPORT_PATH"))
+		})
+	}, spec.Sequential())
+
+	context("when the Gopkg.toml declares a heroku root-package", func() {
+		it.Before(func() {
+			gopkgTomlParser.ParseHerokuMetadataCall.Returns.HerokuMetadata = dep.HerokuMetadata{
+				RootPackage: "github.com/some-org/some-heroku-app",
+			}
+
+			writeGitConfig(`[remote "origin"]
+	url = git@github.com:some-org/some-git-app.git
+`)
+		})
+
+		it("takes priority over the git remote", func() {
+			importPath, source, err := resolver.Resolve(workingDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(importPath).To(Equal("github.com/some-org/some-heroku-app"))
+			Expect(source).To(Equal("Gopkg.toml"))
+		})
+	})
+
+	context("when the app is a git repository", func() {
+		context("with an scp-like origin remote", func() {
+			it.Before(func() {
+				writeGitConfig(`[core]
+	bare = false
+[remote "upstream"]
+	url = https://example.com/some-other-org/some-fork.git
+[remote "origin"]
+	url = git@github.com:some-org/some-git-app.git
+	fetch = +refs/heads/*:refs/remotes/origin/*
+`)
+			})
+
+			it("guesses the import path from the origin remote", func() {
+				importPath, source, err := resolver.Resolve(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(importPath).To(Equal("github.com/some-org/some-git-app"))
+				Expect(source).To(Equal(".git/config"))
+			})
+		})
+
+		context("with only a URL remote that is not origin", func() {
+			it.Before(func() {
+				writeGitConfig(`[remote "upstream"]
+	url = ssh://git@example.com:2222/some-org/some-git-app/
+`)
+			})
+
+			it("guesses the import path from that remote", func() {
+				importPath, _, err := resolver.Resolve(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(importPath).To(Equal("example.com/some-org/some-git-app"))
+			})
+		})
+
+		context("without any remotes", func() {
+			it.Before(func() {
+				writeGitConfig(`[core]
+	bare = false
+`)
+			})
+
+			it("defaults to the app import path", func() {
+				importPath, _, err := resolver.Resolve(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(importPath).To(Equal("app"))
+			})
+		})
+	})
+
+	context("failure cases", func() {
+		context("when the Gopkg.toml cannot be parsed", func() {
+			it.Before(func() {
+				gopkgTomlParser.ParseHerokuMetadataCall.Returns.Error = errors.New("failed to parse Gopkg.toml")
+			})
+
+			it("returns an error", func() {
+				_, _, err := resolver.Resolve(workingDir)
+				Expect(err).To(MatchError("failed to parse Gopkg.toml"))
+			})
+		})
+
+		context("when the import path leaves the GOPATH", func() {
+			it.Before(func() {
+				gopkgTomlParser.ParseHerokuMetadataCall.Returns.HerokuMetadata = dep.HerokuMetadata{
+					RootPackage: "../../some-app",
+				}
+			})
+
+			it("returns an error", func() {
+				_, _, err := resolver.Resolve(workingDir)
+				Expect(err).To(MatchError(`invalid import path "../../some-app" from Gopkg.toml`))
+			})
+		})
+
+		context("when the import path is not made of names", func() {
+			it("returns an error", func() {
+				for rootPackage, message := range map[string]string{
+					".":                     `invalid import path "." from Gopkg.toml`,
+					"./":                    `invalid import path "." from Gopkg.toml`,
+					"github.com//some-app":  `invalid import path "github.com//some-app" from Gopkg.toml`,
+					"github.com/./some-app": `invalid import path "github.com/./some-app" from Gopkg.toml`,
+					"github.com/ /some-app": `invalid import path "github.com/ /some-app" from Gopkg.toml`,
+					"   ":                   `invalid import path "   " from Gopkg.toml`,
+				} {
+					gopkgTomlParser.ParseHerokuMetadataCall.Returns.HerokuMetadata = dep.HerokuMetadata{
+						RootPackage: rootPackage,
+					}
+
+					_, _, err := resolver.Resolve(workingDir)
+					Expect(err).To(MatchError(message), rootPackage)
+				}
+			})
+		})
+
+		context("when the git config cannot be read", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, ".git"), nil, 0600)).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, _, err := resolver.Resolve(workingDir)
+				Expect(err).To(MatchError(ContainSubstring("failed to read git config")))
+			})
+		})
+	})
+}
@@ -0,0 +1,43 @@
+package dep
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/BurntSushi/toml"
+)
+
+// DepReceipt describes the dep that is installed in the dep layer. It is
+// written to dep.toml in the layer so that later buildpacks do not have to run
+// dep to learn about it, and its keys are part of the public API of the
+// buildpack.
+type DepReceipt struct {
+	Version string `toml:"version"`
+
+	// SHA256 and URI identify the artifact that dep was installed from, which
+	// is the source tarball when dep was built from source.
+	SHA256          string `toml:"sha256"`
+	URI             string `toml:"uri"`
+	BuiltFromSource bool   `toml:"built-from-source"`
+
+	Arch string `toml:"arch"`
+
+	// Path is the dep layer, which has the dep executable in its bin
+	// directory.
+	Path string `toml:"path"`
+}
+
+func writeDepReceipt(path string, receipt DepReceipt) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to write dep receipt: %w", err)
+	}
+	defer file.Close()
+
+	err = toml.NewEncoder(file).Encode(receipt)
+	if err != nil {
+		return fmt.Errorf("failed to write dep receipt: %w", err)
+	}
+
+	return nil
+}
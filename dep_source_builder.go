@@ -0,0 +1,145 @@
+package dep
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/paketo-buildpacks/packit/v2/cargo"
+	"github.com/paketo-buildpacks/packit/v2/pexec"
+	"github.com/paketo-buildpacks/packit/v2/postal"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	"github.com/paketo-buildpacks/packit/v2/vacation"
+)
+
+// depImportPath is the import path that the dep source must be built at, as
+// dep predates Go modules and vendors its own dependencies.
+const depImportPath = "github.com/golang/dep"
+
+type DepSourceBuilder struct {
+	transport  postal.Transport
+	executable Executable
+	logger     scribe.Emitter
+}
+
+func NewDepSourceBuilder(transport postal.Transport, executable Executable, logger scribe.Emitter) DepSourceBuilder {
+	return DepSourceBuilder{
+		transport:  transport,
+		executable: executable,
+		logger:     logger,
+	}
+}
+
+// Resolve picks the newest dependency in the buildpack.toml at path with the
+// given id that satisfies the version constraint and has a source artifact,
+// regardless of the stacks it was prebuilt for.
+func (b DepSourceBuilder) Resolve(path, id, version string) (postal.Dependency, error) {
+	buildpack, err := parseBuildpackMetadata(path)
+	if err != nil {
+		return postal.Dependency{}, err
+	}
+
+	version = normalizeConstraint(version, buildpack.Metadata.DefaultVersions[id])
+
+	constraint, err := semver.NewConstraint(version)
+	if err != nil {
+		return postal.Dependency{}, err
+	}
+
+	var candidates []postal.Dependency
+	for _, dependency := range buildpack.Metadata.Dependencies {
+		if dependency.ID != id || dependency.Source == "" || dependency.SourceSHA256 == "" {
+			continue
+		}
+
+		v, err := semver.NewVersion(dependency.Version)
+		if err != nil {
+			return postal.Dependency{}, err
+		}
+
+		if constraint.Check(v) {
+			candidates = append(candidates, dependency.Dependency)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return postal.Dependency{}, fmt.Errorf("failed to satisfy %q dependency version constraint %q: no versions with a source artifact", id, version)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return semver.MustParse(candidates[i].Version).GreaterThan(semver.MustParse(candidates[j].Version))
+	})
+
+	return candidates[0], nil
+}
+
+// Build fetches the source of the given dependency, verifies it against its
+// source_sha256, and compiles dep into the bin directory of layerPath using
+// the go executable provided by an earlier buildpack.
+func (b DepSourceBuilder) Build(dependency postal.Dependency, cnbPath, layerPath string) error {
+	gopath, err := os.MkdirTemp("", "gopath")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary GOPATH: %w", err)
+	}
+	defer os.RemoveAll(gopath)
+
+	sourcePath := filepath.Join(gopath, "src", filepath.FromSlash(depImportPath))
+	err = os.MkdirAll(sourcePath, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("failed to create temporary GOPATH: %w", err)
+	}
+
+	bundle, err := b.transport.Drop(cnbPath, dependency.Source)
+	if err != nil {
+		return fmt.Errorf("failed to fetch dependency source: %w", err)
+	}
+	defer bundle.Close()
+
+	validatedReader := cargo.NewValidatedReader(bundle, fmt.Sprintf("sha256:%s", dependency.SourceSHA256))
+	err = vacation.NewArchive(validatedReader).StripComponents(1).Decompress(sourcePath)
+	if err != nil {
+		return fmt.Errorf("failed to decompress dependency source: %w", err)
+	}
+
+	ok, err := validatedReader.Valid()
+	if err != nil {
+		return fmt.Errorf("failed to validate dependency source: %w", err)
+	}
+
+	if !ok {
+		return errors.New("failed to validate dependency source: checksum does not match")
+	}
+
+	args := []string{
+		"build",
+		"-o", filepath.Join(layerPath, "bin", "dep"),
+		"-ldflags", fmt.Sprintf("-s -w -X main.version=v%s", dependency.Version),
+		fmt.Sprintf("%s/cmd/dep", depImportPath),
+	}
+
+	b.logger.Subprocess("Running 'go %s'", strings.Join(args, " "))
+
+	buffer := bytes.NewBuffer(nil)
+	err = b.executable.Execute(pexec.Execution{
+		Args: args,
+		Env: append(os.Environ(),
+			fmt.Sprintf("GOPATH=%s", gopath),
+			"GO111MODULE=off",
+			"CGO_ENABLED=0",
+		),
+		Dir:    sourcePath,
+		Stdout: buffer,
+		Stderr: buffer,
+	})
+	if err != nil {
+		b.logger.Detail(buffer.String())
+		return fmt.Errorf("failed to build dep from source: %w", err)
+	}
+
+	return nil
+}
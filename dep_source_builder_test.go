@@ -0,0 +1,246 @@
+package dep_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/dep"
+	"github.com/paketo-buildpacks/dep/fakes"
+	"github.com/paketo-buildpacks/packit/v2/pexec"
+	"github.com/paketo-buildpacks/packit/v2/postal"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDepSourceBuilder(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		cnbDir     string
+		layerDir   string
+		buffer     *bytes.Buffer
+		source     []byte
+		sourceSHA  string
+		transport  *fakes.Transport
+		executable *fakes.Executable
+		executions []pexec.Execution
+
+		builder dep.DepSourceBuilder
+	)
+
+	it.Before(func() {
+		var err error
+		cnbDir, err = os.MkdirTemp("", "cnb")
+		Expect(err).NotTo(HaveOccurred())
+
+		layerDir, err = os.MkdirTemp("", "layer")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = os.WriteFile(filepath.Join(cnbDir, "buildpack.toml"), []byte(`
+[metadata]
+  [metadata.default-versions]
+    dep = "0.5.*"
+
+  [[metadata.dependencies]]
+    id = "dep"
+    source = "https://example.com/dep-0.5.3.tar.gz"
+    source_sha256 = "some-source-sha"
+    stacks = ["some-stack"]
+    version = "0.5.3"
+
+  [[metadata.dependencies]]
+    id = "dep"
+    source = "https://example.com/dep-0.5.4.tar.gz"
+    source_sha256 = "other-source-sha"
+    stacks = ["some-other-stack"]
+    version = "0.5.4"
+
+  [[metadata.dependencies]]
+    id = "dep"
+    stacks = ["some-stack"]
+    version = "0.6.0"
+`), 0600)
+		Expect(err).NotTo(HaveOccurred())
+
+		buf := bytes.NewBuffer(nil)
+		gw := gzip.NewWriter(buf)
+		tw := tar.NewWriter(gw)
+
+		content := []byte("package main")
+		Expect(tw.WriteHeader(&tar.Header{Name: "dep-0.5.4/cmd/dep/main.go", Mode: 0644, Size: int64(len(content))})).To(Succeed())
+		_, err = tw.Write(content)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(tw.Close()).To(Succeed())
+		Expect(gw.Close()).To(Succeed())
+
+		source = buf.Bytes()
+		sum := sha256.Sum256(source)
+		sourceSHA = hex.EncodeToString(sum[:])
+
+		transport = &fakes.Transport{}
+		transport.DropCall.Stub = func(string, string) (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(source)), nil
+		}
+
+		executions = []pexec.Execution{}
+		executable = &fakes.Executable{}
+		executable.ExecuteCall.Stub = func(execution pexec.Execution) error {
+			executions = append(executions, execution)
+
+			_, err := os.Stat(filepath.Join(execution.Dir, "cmd", "dep", "main.go"))
+			return err
+		}
+
+		buffer = bytes.NewBuffer(nil)
+		builder = dep.NewDepSourceBuilder(transport, executable, scribe.NewEmitter(buffer))
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(cnbDir)).To(Succeed())
+		Expect(os.RemoveAll(layerDir)).To(Succeed())
+	})
+
+	context("Resolve", func() {
+		it("returns the newest version with a source artifact regardless of stack", func() {
+			dependency, err := builder.Resolve(filepath.Join(cnbDir, "buildpack.toml"), "dep", "default")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dependency.Version).To(Equal("0.5.4"))
+			Expect(dependency.Source).To(Equal("https://example.com/dep-0.5.4.tar.gz"))
+			Expect(dependency.SourceSHA256).To(Equal("other-source-sha"))
+		})
+
+		it("honors the version constraint", func() {
+			dependency, err := builder.Resolve(filepath.Join(cnbDir, "buildpack.toml"), "dep", "~0.5.3, <0.5.4")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dependency.Version).To(Equal("0.5.3"))
+		})
+
+		context("failure cases", func() {
+			context("when the buildpack.toml cannot be parsed", func() {
+				it("returns an error", func() {
+					_, err := builder.Resolve(filepath.Join(cnbDir, "missing.toml"), "dep", "default")
+					Expect(err).To(MatchError(ContainSubstring("failed to parse buildpack.toml")))
+				})
+			})
+
+			context("when the version constraint is invalid", func() {
+				it("returns an error", func() {
+					_, err := builder.Resolve(filepath.Join(cnbDir, "buildpack.toml"), "dep", "not-a-constraint")
+					Expect(err).To(HaveOccurred())
+				})
+			})
+
+			context("when no version with a source artifact matches", func() {
+				it("returns an error", func() {
+					_, err := builder.Resolve(filepath.Join(cnbDir, "buildpack.toml"), "dep", "0.6.*")
+					Expect(err).To(MatchError(`failed to satisfy "dep" dependency version constraint "0.6.*": no versions with a source artifact`))
+				})
+			})
+		})
+	})
+
+	context("Build", func() {
+		var dependency postal.Dependency
+
+		it.Before(func() {
+			dependency = postal.Dependency{
+				ID:           "dep",
+				Source:       "https://example.com/dep-0.5.4.tar.gz",
+				SourceSHA256: sourceSHA,
+				Version:      "0.5.4",
+			}
+		})
+
+		it("fetches the verified source and builds dep inside of a GOPATH", func() {
+			err := builder.Build(dependency, cnbDir, layerDir)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(transport.DropCall.Receives.Root).To(Equal(cnbDir))
+			Expect(transport.DropCall.Receives.Uri).To(Equal("https://example.com/dep-0.5.4.tar.gz"))
+
+			Expect(executions).To(HaveLen(1))
+			Expect(executions[0].Args).To(Equal([]string{
+				"build",
+				"-o", filepath.Join(layerDir, "bin", "dep"),
+				"-ldflags", "-s -w -X main.version=v0.5.4",
+				"github.com/golang/dep/cmd/dep",
+			}))
+			Expect(executions[0].Dir).To(HaveSuffix(filepath.Join("src", "github.com", "golang", "dep")))
+
+			gopath := filepath.Dir(filepath.Dir(filepath.Dir(filepath.Dir(executions[0].Dir))))
+			Expect(executions[0].Env).To(ContainElement(fmt.Sprintf("GOPATH=%s", gopath)))
+			Expect(executions[0].Env).To(ContainElement("GO111MODULE=off"))
+			Expect(gopath).NotTo(BeADirectory())
+
+			Expect(buffer.String()).To(ContainSubstring("Running 'go build -o"))
+		})
+
+		context("failure cases", func() {
+			context("when the source cannot be fetched", func() {
+				it.Before(func() {
+					transport.DropCall.Stub = nil
+					transport.DropCall.Returns.Error = errors.New("some-error")
+				})
+
+				it("returns an error", func() {
+					err := builder.Build(dependency, cnbDir, layerDir)
+					Expect(err).To(MatchError("failed to fetch dependency source: some-error"))
+				})
+			})
+
+			context("when the source checksum does not match", func() {
+				it.Before(func() {
+					dependency.SourceSHA256 = "some-other-sha"
+				})
+
+				it("returns an error", func() {
+					err := builder.Build(dependency, cnbDir, layerDir)
+					Expect(err).To(MatchError(ContainSubstring("checksum does not match")))
+
+					Expect(executions).To(BeEmpty())
+				})
+			})
+
+			context("when the source cannot be decompressed", func() {
+				it.Before(func() {
+					transport.DropCall.Stub = func(string, string) (io.ReadCloser, error) {
+						return io.NopCloser(bytes.NewBufferString("\x1f\x8b not really gzip")), nil
+					}
+				})
+
+				it("returns an error", func() {
+					err := builder.Build(dependency, cnbDir, layerDir)
+					Expect(err).To(MatchError(ContainSubstring("failed to decompress dependency source")))
+				})
+			})
+
+			context("when go build fails", func() {
+				it.Before(func() {
+					executable.ExecuteCall.Stub = func(execution pexec.Execution) error {
+						fmt.Fprintln(execution.Stderr, "some build error")
+						return errors.New("exit status 1")
+					}
+				})
+
+				it("returns an error and logs the output", func() {
+					err := builder.Build(dependency, cnbDir, layerDir)
+					Expect(err).To(MatchError("failed to build dep from source: exit status 1"))
+
+					Expect(buffer.String()).To(ContainSubstring("some build error"))
+				})
+			})
+		})
+	})
+}
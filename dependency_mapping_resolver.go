@@ -0,0 +1,46 @@
+package dep
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/paketo-buildpacks/packit/v2/servicebindings"
+)
+
+//go:generate faux --interface BindingResolver --output fakes/binding_resolver.go
+type BindingResolver interface {
+	Resolve(typ, provider, platformDir string) ([]servicebindings.Binding, error)
+}
+
+type DependencyMappingResolver struct {
+	bindingResolver BindingResolver
+}
+
+func NewDependencyMappingResolver(bindingResolver BindingResolver) DependencyMappingResolver {
+	return DependencyMappingResolver{
+		bindingResolver: bindingResolver,
+	}
+}
+
+// FindDependencyMapping returns the URI that a "dependency-mapping" service
+// binding maps the given dependency SHA256 to. It returns an empty string when
+// no binding maps the dependency.
+func (r DependencyMappingResolver) FindDependencyMapping(sha256, platformDir string) (string, error) {
+	bindings, err := r.bindingResolver.Resolve("dependency-mapping", "", platformDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve 'dependency-mapping' binding: %w", err)
+	}
+
+	for _, binding := range bindings {
+		if entry, ok := binding.Entries[sha256]; ok {
+			uri, err := entry.ReadString()
+			if err != nil {
+				return "", fmt.Errorf("failed to read 'dependency-mapping' binding %q: %w", binding.Name, err)
+			}
+
+			return strings.TrimSpace(uri), nil
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,95 @@
+package dep_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/dep"
+	"github.com/paketo-buildpacks/dep/fakes"
+	"github.com/paketo-buildpacks/packit/v2/servicebindings"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDependencyMappingResolver(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		bindingDir      string
+		bindingResolver *fakes.BindingResolver
+		resolver        dep.DependencyMappingResolver
+	)
+
+	it.Before(func() {
+		var err error
+		bindingDir, err = os.MkdirTemp("", "binding")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.WriteFile(filepath.Join(bindingDir, "some-sha"), []byte("file:///some/dep.tgz\n"), 0600)).To(Succeed())
+
+		bindingResolver = &fakes.BindingResolver{}
+		bindingResolver.ResolveCall.Returns.BindingSlice = []servicebindings.Binding{
+			{
+				Name: "some-binding",
+				Path: bindingDir,
+				Type: "dependency-mapping",
+				Entries: map[string]*servicebindings.Entry{
+					"some-sha": servicebindings.NewEntry(filepath.Join(bindingDir, "some-sha")),
+				},
+			},
+		}
+
+		resolver = dep.NewDependencyMappingResolver(bindingResolver)
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(bindingDir)).To(Succeed())
+	})
+
+	context("FindDependencyMapping", func() {
+		it("returns the URI that the dependency is mapped to", func() {
+			uri, err := resolver.FindDependencyMapping("some-sha", "some-platform")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(uri).To(Equal("file:///some/dep.tgz"))
+
+			Expect(bindingResolver.ResolveCall.Receives.Typ).To(Equal("dependency-mapping"))
+			Expect(bindingResolver.ResolveCall.Receives.Provider).To(BeEmpty())
+			Expect(bindingResolver.ResolveCall.Receives.PlatformDir).To(Equal("some-platform"))
+		})
+
+		context("when the dependency is not mapped", func() {
+			it("returns an empty URI", func() {
+				uri, err := resolver.FindDependencyMapping("some-other-sha", "some-platform")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(uri).To(BeEmpty())
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the bindings cannot be resolved", func() {
+				it.Before(func() {
+					bindingResolver.ResolveCall.Returns.Error = errors.New("some-error")
+				})
+
+				it("returns an error", func() {
+					_, err := resolver.FindDependencyMapping("some-sha", "some-platform")
+					Expect(err).To(MatchError("failed to resolve 'dependency-mapping' binding: some-error"))
+				})
+			})
+
+			context("when the binding entry cannot be read", func() {
+				it.Before(func() {
+					Expect(os.Remove(filepath.Join(bindingDir, "some-sha"))).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := resolver.FindDependencyMapping("some-sha", "some-platform")
+					Expect(err).To(MatchError(ContainSubstring(`failed to read 'dependency-mapping' binding "some-binding"`)))
+				})
+			})
+		})
+	})
+}
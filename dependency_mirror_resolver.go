@@ -0,0 +1,89 @@
+package dep
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+type DependencyMirrorResolver struct {
+	bindingResolver BindingResolver
+}
+
+func NewDependencyMirrorResolver(bindingResolver BindingResolver) DependencyMirrorResolver {
+	return DependencyMirrorResolver{
+		bindingResolver: bindingResolver,
+	}
+}
+
+// FindDependencyMirror returns the URI that the given dependency URI should be
+// downloaded from when a mirror is configured for its host. It returns an
+// empty string when no mirror is configured.
+//
+// Mirrors are looked up, in order of precedence, from:
+//
+//  1. the BP_DEPENDENCY_MIRROR_<HOST> environment variable, where <HOST> is
+//     the upper-cased hostname with "." replaced by "_" and "-" replaced by "__"
+//  2. the BP_DEPENDENCY_MIRROR environment variable
+//  3. a "dependency-mirror" service binding entry named after the hostname
+//  4. a "dependency-mirror" service binding entry named "default"
+//
+// The path of the original URI is appended to the mirror. A mirror may include
+// the "{originalHost}" placeholder, which is replaced with the original
+// hostname.
+func (r DependencyMirrorResolver) FindDependencyMirror(uri, platformDir string) (string, error) {
+	original, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse dependency uri: %w", err)
+	}
+
+	hostname := original.Hostname()
+	if hostname == "" {
+		return "", nil
+	}
+
+	mirror, err := r.findMirror(hostname, platformDir)
+	if err != nil {
+		return "", err
+	}
+
+	if mirror == "" {
+		return "", nil
+	}
+
+	mirror = strings.ReplaceAll(mirror, "{originalHost}", hostname)
+
+	return strings.TrimSuffix(mirror, "/") + original.EscapedPath(), nil
+}
+
+func (r DependencyMirrorResolver) findMirror(hostname, platformDir string) (string, error) {
+	replacer := strings.NewReplacer("-", "__", ".", "_")
+	if mirror, ok := os.LookupEnv(fmt.Sprintf("BP_DEPENDENCY_MIRROR_%s", strings.ToUpper(replacer.Replace(hostname)))); ok {
+		return mirror, nil
+	}
+
+	if mirror, ok := os.LookupEnv("BP_DEPENDENCY_MIRROR"); ok {
+		return mirror, nil
+	}
+
+	bindings, err := r.bindingResolver.Resolve("dependency-mirror", "", platformDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve 'dependency-mirror' binding: %w", err)
+	}
+
+	for _, key := range []string{hostname, "default"} {
+		for _, binding := range bindings {
+			if entry, ok := binding.Entries[key]; ok {
+				mirror, err := entry.ReadString()
+				if err != nil {
+					return "", fmt.Errorf("failed to read 'dependency-mirror' binding %q: %w", binding.Name, err)
+				}
+
+				return strings.TrimSpace(mirror), nil
+			}
+		}
+	}
+
+	return "", nil
+}
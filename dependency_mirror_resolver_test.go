@@ -0,0 +1,162 @@
+package dep_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/dep"
+	"github.com/paketo-buildpacks/dep/fakes"
+	"github.com/paketo-buildpacks/packit/v2/servicebindings"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDependencyMirrorResolver(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		bindingDir      string
+		bindingResolver *fakes.BindingResolver
+		resolver        dep.DependencyMirrorResolver
+	)
+
+	it.Before(func() {
+		var err error
+		bindingDir, err = os.MkdirTemp("", "binding")
+		Expect(err).NotTo(HaveOccurred())
+
+		bindingResolver = &fakes.BindingResolver{}
+		resolver = dep.NewDependencyMirrorResolver(bindingResolver)
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(bindingDir)).To(Succeed())
+	})
+
+	context("FindDependencyMirror", func() {
+		context("when no mirror is configured", func() {
+			it("returns an empty URI", func() {
+				uri, err := resolver.FindDependencyMirror("https://some-host.example.com/some/dep.tgz", "some-platform")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(uri).To(BeEmpty())
+
+				Expect(bindingResolver.ResolveCall.Receives.Typ).To(Equal("dependency-mirror"))
+				Expect(bindingResolver.ResolveCall.Receives.PlatformDir).To(Equal("some-platform"))
+			})
+		})
+
+		context("when BP_DEPENDENCY_MIRROR is set", func() {
+			it.Before(func() {
+				Expect(os.Setenv("BP_DEPENDENCY_MIRROR", "https://mirror.example.com/{originalHost}/")).To(Succeed())
+			})
+
+			it.After(func() {
+				Expect(os.Unsetenv("BP_DEPENDENCY_MIRROR")).To(Succeed())
+			})
+
+			it("rewrites the URI to the mirror", func() {
+				uri, err := resolver.FindDependencyMirror("https://some-host.example.com/some/dep.tgz", "some-platform")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(uri).To(Equal("https://mirror.example.com/some-host.example.com/some/dep.tgz"))
+
+				Expect(bindingResolver.ResolveCall.CallCount).To(Equal(0))
+			})
+
+			context("when a hostname specific mirror is also set", func() {
+				it.Before(func() {
+					Expect(os.Setenv("BP_DEPENDENCY_MIRROR_SOME__HOST_EXAMPLE_COM", "file:///some/local/mirror")).To(Succeed())
+				})
+
+				it.After(func() {
+					Expect(os.Unsetenv("BP_DEPENDENCY_MIRROR_SOME__HOST_EXAMPLE_COM")).To(Succeed())
+				})
+
+				it("prefers the hostname specific mirror", func() {
+					uri, err := resolver.FindDependencyMirror("https://some-host.example.com/some/dep.tgz", "some-platform")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(uri).To(Equal("file:///some/local/mirror/some/dep.tgz"))
+				})
+			})
+		}, spec.Sequential())
+
+		context("when a dependency-mirror binding is configured", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(bindingDir, "default"), []byte("https://default-mirror.example.com\n"), 0600)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(bindingDir, "some-host.example.com"), []byte("https://host-mirror.example.com/{originalHost}\n"), 0600)).To(Succeed())
+
+				bindingResolver.ResolveCall.Returns.BindingSlice = []servicebindings.Binding{
+					{
+						Name: "some-binding",
+						Path: bindingDir,
+						Type: "dependency-mirror",
+						Entries: map[string]*servicebindings.Entry{
+							"default":               servicebindings.NewEntry(filepath.Join(bindingDir, "default")),
+							"some-host.example.com": servicebindings.NewEntry(filepath.Join(bindingDir, "some-host.example.com")),
+						},
+					},
+				}
+			})
+
+			it("prefers the hostname specific entry", func() {
+				uri, err := resolver.FindDependencyMirror("https://some-host.example.com/some/dep.tgz", "some-platform")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(uri).To(Equal("https://host-mirror.example.com/some-host.example.com/some/dep.tgz"))
+			})
+
+			it("falls back to the default entry", func() {
+				uri, err := resolver.FindDependencyMirror("https://other-host.example.com/some/dep.tgz", "some-platform")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(uri).To(Equal("https://default-mirror.example.com/some/dep.tgz"))
+			})
+		})
+
+		context("when the URI has no host", func() {
+			it("returns an empty URI", func() {
+				uri, err := resolver.FindDependencyMirror("file:///some/dep.tgz", "some-platform")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(uri).To(BeEmpty())
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the URI cannot be parsed", func() {
+				it("returns an error", func() {
+					_, err := resolver.FindDependencyMirror("%%%", "some-platform")
+					Expect(err).To(MatchError(ContainSubstring("failed to parse dependency uri")))
+				})
+			})
+
+			context("when the bindings cannot be resolved", func() {
+				it.Before(func() {
+					bindingResolver.ResolveCall.Returns.Error = errors.New("some-error")
+				})
+
+				it("returns an error", func() {
+					_, err := resolver.FindDependencyMirror("https://some-host.example.com/some/dep.tgz", "some-platform")
+					Expect(err).To(MatchError("failed to resolve 'dependency-mirror' binding: some-error"))
+				})
+			})
+
+			context("when the binding entry cannot be read", func() {
+				it.Before(func() {
+					bindingResolver.ResolveCall.Returns.BindingSlice = []servicebindings.Binding{
+						{
+							Name: "some-binding",
+							Entries: map[string]*servicebindings.Entry{
+								"default": servicebindings.NewEntry(filepath.Join(bindingDir, "missing")),
+							},
+						},
+					}
+				})
+
+				it("returns an error", func() {
+					_, err := resolver.FindDependencyMirror("https://some-host.example.com/some/dep.tgz", "some-platform")
+					Expect(err).To(MatchError(ContainSubstring(`failed to read 'dependency-mirror' binding "some-binding"`)))
+				})
+			})
+		})
+	})
+}
@@ -1,16 +1,155 @@
 package dep
 
-import "github.com/paketo-buildpacks/packit/v2"
+import (
+	"path/filepath"
+	"strings"
 
-func Detect() packit.DetectFunc {
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/fs"
+)
+
+//go:generate faux --interface VersionParser --output fakes/version_parser.go
+type VersionParser interface {
+	ParseVersion(path string) (version string, err error)
+}
+
+//go:generate faux --interface GopkgParser --output fakes/gopkg_parser.go
+type GopkgParser interface {
+	ParseVersion(path string) (version string, err error)
+	ParseHerokuMetadata(path string) (HerokuMetadata, error)
+}
+
+type BuildPlanMetadata struct {
+	Version       string   `toml:"version,omitempty"`
+	VersionSource string   `toml:"version-source,omitempty"`
+	Build         bool     `toml:"build"`
+	Install       []string `toml:"install,omitempty"`
+}
+
+func Detect(buildpackYMLParser VersionParser, gopkgTomlParser GopkgParser) packit.DetectFunc {
 	return func(context packit.DetectContext) (packit.DetectResult, error) {
-		return packit.DetectResult{
-			Plan: packit.BuildPlan{
+		plan := packit.BuildPlan{
+			Provides: []packit.BuildPlanProvision{
+				{Name: Dep},
+			},
+		}
+
+		version, err := buildpackYMLParser.ParseVersion(filepath.Join(context.WorkingDir, "buildpack.yml"))
+		if err != nil {
+			return packit.DetectResult{}, err
+		}
+
+		if version != "" {
+			plan.Requires = append(plan.Requires, packit.BuildPlanRequirement{
+				Name: Dep,
+				Metadata: BuildPlanMetadata{
+					Version:       version,
+					VersionSource: "buildpack.yml",
+					Build:         true,
+				},
+			})
+		}
+
+		// When the app itself is a dep project, require dep for the build so
+		// that no separate buildpack is needed to request it. Otherwise, only
+		// provide dep for downstream buildpacks that require it.
+		for _, file := range []string{GopkgToml, GopkgLock} {
+			exists, err := fs.Exists(filepath.Join(context.WorkingDir, file))
+			if err != nil {
+				return packit.DetectResult{}, err
+			}
+
+			if exists {
+				version, err := gopkgTomlParser.ParseVersion(filepath.Join(context.WorkingDir, GopkgToml))
+				if err != nil {
+					return packit.DetectResult{}, err
+				}
+
+				// Apps migrated from the Heroku and Cloud Foundry Go buildpacks
+				// declare their Go version and install packages in Gopkg.toml. The Go
+				// version is required from the Go distribution buildpack, while the
+				// install packages are kept on the dep requirement, so that build can
+				// export them to the Go build buildpack.
+				heroku, err := gopkgTomlParser.ParseHerokuMetadata(filepath.Join(context.WorkingDir, GopkgToml))
+				if err != nil {
+					return packit.DetectResult{}, err
+				}
+
+				metadata := BuildPlanMetadata{
+					Build:   true,
+					Install: heroku.Install,
+				}
+				if version != "" {
+					metadata.Version = version
+					metadata.VersionSource = GopkgToml
+				}
+
+				plan.Requires = append(plan.Requires, packit.BuildPlanRequirement{
+					Name:     Dep,
+					Metadata: metadata,
+				})
+
+				if heroku.GoVersion != "" {
+					plan.Requires = append(plan.Requires, packit.BuildPlanRequirement{
+						Name: "go",
+						Metadata: BuildPlanMetadata{
+							Version:       goVersionConstraint(heroku.GoVersion),
+							VersionSource: GopkgToml,
+							Build:         true,
+						},
+					})
+				}
+
+				break
+			}
+		}
+
+		// Building dep from source needs a Go toolchain from an earlier
+		// buildpack in case no prebuilt dep matches the stack.
+		buildFromSource, err := parseBoolEnv("BP_DEP_BUILD_FROM_SOURCE")
+		if err != nil {
+			return packit.DetectResult{}, err
+		}
+
+		if buildFromSource {
+			plan.Requires = append(plan.Requires, packit.BuildPlanRequirement{
+				Name:     "go",
+				Metadata: BuildPlanMetadata{Build: true},
+			})
+		}
+
+		// An app with a Gopkg.toml is vendored by dep ensure, which later
+		// buildpacks can depend on by requiring dep-vendor. Every provision must
+		// be required by some buildpack, so the plan without dep-vendor remains
+		// as an alternative.
+		vendorable, err := fs.Exists(filepath.Join(context.WorkingDir, GopkgToml))
+		if err != nil {
+			return packit.DetectResult{}, err
+		}
+
+		if vendorable {
+			plan = packit.BuildPlan{
 				Provides: []packit.BuildPlanProvision{
-					{Name: "dep"},
+					{Name: Dep},
+					{Name: DepVendor},
 				},
-				Requires: nil,
-			},
-		}, nil
+				Requires: plan.Requires,
+				Or:       []packit.BuildPlan{plan},
+			}
+		}
+
+		return packit.DetectResult{Plan: plan}, nil
 	}
 }
+
+// goVersionConstraint converts a Heroku go-version such as "go1.12" or
+// "go1.12.5" into a version constraint. Like on Heroku, a version without a
+// patch selects the latest patch of that minor version.
+func goVersionConstraint(goVersion string) string {
+	version := strings.TrimPrefix(goVersion, "go")
+	if strings.Count(version, ".") < 2 {
+		version += ".*"
+	}
+
+	return version
+}
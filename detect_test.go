@@ -1,10 +1,13 @@
 package dep_test
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/paketo-buildpacks/dep"
+	"github.com/paketo-buildpacks/dep/fakes"
 	"github.com/paketo-buildpacks/packit/v2"
 	"github.com/sclevine/spec"
 
@@ -15,8 +18,11 @@ func testDetect(t *testing.T, context spec.G, it spec.S) {
 	var (
 		Expect = NewWithT(t).Expect
 
-		workingDir string
-		detect     packit.DetectFunc
+		workingDir         string
+		buildpackYMLParser *fakes.VersionParser
+		gopkgTomlParser    *fakes.GopkgParser
+
+		detect packit.DetectFunc
 	)
 
 	it.Before(func() {
@@ -24,7 +30,10 @@ func testDetect(t *testing.T, context spec.G, it spec.S) {
 		workingDir, err = os.MkdirTemp("", "working-dir")
 		Expect(err).NotTo(HaveOccurred())
 
-		detect = dep.Detect()
+		buildpackYMLParser = &fakes.VersionParser{}
+		gopkgTomlParser = &fakes.GopkgParser{}
+
+		detect = dep.Detect(buildpackYMLParser, gopkgTomlParser)
 	})
 
 	it.After(func() {
@@ -42,6 +51,388 @@ func testDetect(t *testing.T, context spec.G, it spec.S) {
 					{Name: "dep"},
 				},
 			}))
+
+			Expect(buildpackYMLParser.ParseVersionCall.Receives.Path).To(Equal(filepath.Join(workingDir, "buildpack.yml")))
+			Expect(gopkgTomlParser.ParseVersionCall.CallCount).To(Equal(0))
+		})
+	})
+
+	context("when the buildpack.yml requests a version", func() {
+		it.Before(func() {
+			buildpackYMLParser.ParseVersionCall.Returns.Version = "0.5.*"
+		})
+
+		it("returns a plan that requires that version of dep", func() {
+			result, err := detect(packit.DetectContext{
+				WorkingDir: workingDir,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Plan).To(Equal(packit.BuildPlan{
+				Provides: []packit.BuildPlanProvision{
+					{Name: "dep"},
+				},
+				Requires: []packit.BuildPlanRequirement{
+					{
+						Name: "dep",
+						Metadata: dep.BuildPlanMetadata{
+							Version:       "0.5.*",
+							VersionSource: "buildpack.yml",
+							Build:         true,
+						},
+					},
+				},
+			}))
+		})
+	})
+
+	context("when the working directory contains a Gopkg.toml", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(workingDir, "Gopkg.toml"), nil, 0600)).To(Succeed())
+		})
+
+		it("returns a plan that provides dep and dep-vendor and requires dep", func() {
+			result, err := detect(packit.DetectContext{
+				WorkingDir: workingDir,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Plan).To(Equal(packit.BuildPlan{
+				Provides: []packit.BuildPlanProvision{
+					{Name: "dep"},
+					{Name: "dep-vendor"},
+				},
+				Requires: []packit.BuildPlanRequirement{
+					{
+						Name: "dep",
+						Metadata: dep.BuildPlanMetadata{
+							Build: true,
+						},
+					},
+				},
+				Or: []packit.BuildPlan{
+					{
+						Provides: []packit.BuildPlanProvision{
+							{Name: "dep"},
+						},
+						Requires: []packit.BuildPlanRequirement{
+							{
+								Name: "dep",
+								Metadata: dep.BuildPlanMetadata{
+									Build: true,
+								},
+							},
+						},
+					},
+				},
+			}))
+		})
+	})
+
+	context("when the Gopkg.toml requests a version", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(workingDir, "Gopkg.toml"), nil, 0600)).To(Succeed())
+			gopkgTomlParser.ParseVersionCall.Returns.Version = "~0.5"
+		})
+
+		it("returns a plan that requires that version of dep", func() {
+			result, err := detect(packit.DetectContext{
+				WorkingDir: workingDir,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Plan).To(Equal(packit.BuildPlan{
+				Provides: []packit.BuildPlanProvision{
+					{Name: "dep"},
+					{Name: "dep-vendor"},
+				},
+				Requires: []packit.BuildPlanRequirement{
+					{
+						Name: "dep",
+						Metadata: dep.BuildPlanMetadata{
+							Version:       "~0.5",
+							VersionSource: "Gopkg.toml",
+							Build:         true,
+						},
+					},
+				},
+				Or: []packit.BuildPlan{
+					{
+						Provides: []packit.BuildPlanProvision{
+							{Name: "dep"},
+						},
+						Requires: []packit.BuildPlanRequirement{
+							{
+								Name: "dep",
+								Metadata: dep.BuildPlanMetadata{
+									Version:       "~0.5",
+									VersionSource: "Gopkg.toml",
+									Build:         true,
+								},
+							},
+						},
+					},
+				},
+			}))
+
+			Expect(gopkgTomlParser.ParseVersionCall.Receives.Path).To(Equal(filepath.Join(workingDir, "Gopkg.toml")))
+		})
+	})
+
+	context("when the Gopkg.toml contains heroku metadata", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(workingDir, "Gopkg.toml"), nil, 0600)).To(Succeed())
+			gopkgTomlParser.ParseHerokuMetadataCall.Returns.HerokuMetadata = dep.HerokuMetadata{
+				RootPackage: "github.com/some-org/some-app",
+				GoVersion:   "go1.12",
+				Install:     []string{"./cmd/some-app"},
+			}
+		})
+
+		it("requires that version of go and keeps the install packages on the dep requirement", func() {
+			result, err := detect(packit.DetectContext{
+				WorkingDir: workingDir,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Plan).To(Equal(packit.BuildPlan{
+				Provides: []packit.BuildPlanProvision{
+					{Name: "dep"},
+					{Name: "dep-vendor"},
+				},
+				Requires: []packit.BuildPlanRequirement{
+					{
+						Name: "dep",
+						Metadata: dep.BuildPlanMetadata{
+							Build:   true,
+							Install: []string{"./cmd/some-app"},
+						},
+					},
+					{
+						Name: "go",
+						Metadata: dep.BuildPlanMetadata{
+							Version:       "1.12.*",
+							VersionSource: "Gopkg.toml",
+							Build:         true,
+						},
+					},
+				},
+				Or: []packit.BuildPlan{
+					{
+						Provides: []packit.BuildPlanProvision{
+							{Name: "dep"},
+						},
+						Requires: []packit.BuildPlanRequirement{
+							{
+								Name: "dep",
+								Metadata: dep.BuildPlanMetadata{
+									Build:   true,
+									Install: []string{"./cmd/some-app"},
+								},
+							},
+							{
+								Name: "go",
+								Metadata: dep.BuildPlanMetadata{
+									Version:       "1.12.*",
+									VersionSource: "Gopkg.toml",
+									Build:         true,
+								},
+							},
+						},
+					},
+				},
+			}))
+
+			Expect(gopkgTomlParser.ParseHerokuMetadataCall.Receives.Path).To(Equal(filepath.Join(workingDir, "Gopkg.toml")))
+		})
+
+		context("when the go-version includes a patch version", func() {
+			it.Before(func() {
+				gopkgTomlParser.ParseHerokuMetadataCall.Returns.HerokuMetadata = dep.HerokuMetadata{
+					GoVersion: "go1.12.5",
+				}
+			})
+
+			it("requires exactly that version of go", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Plan.Requires).To(ContainElement(packit.BuildPlanRequirement{
+					Name: "go",
+					Metadata: dep.BuildPlanMetadata{
+						Version:       "1.12.5",
+						VersionSource: "Gopkg.toml",
+						Build:         true,
+					},
+				}))
+			})
+		})
+
+		context("when only install packages are given", func() {
+			it.Before(func() {
+				gopkgTomlParser.ParseHerokuMetadataCall.Returns.HerokuMetadata = dep.HerokuMetadata{
+					Install: []string{"./cmd/some-app"},
+				}
+			})
+
+			it("does not require go", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Plan.Requires).To(Equal([]packit.BuildPlanRequirement{
+					{
+						Name: "dep",
+						Metadata: dep.BuildPlanMetadata{
+							Build:   true,
+							Install: []string{"./cmd/some-app"},
+						},
+					},
+				}))
+			})
+		})
+
+		context("when only a root-package is given", func() {
+			it.Before(func() {
+				gopkgTomlParser.ParseHerokuMetadataCall.Returns.HerokuMetadata = dep.HerokuMetadata{
+					RootPackage: "github.com/some-org/some-app",
+				}
+			})
+
+			it("does not require go", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Plan.Requires).To(HaveLen(1))
+				Expect(result.Plan.Requires[0].Name).To(Equal("dep"))
+			})
+		})
+	})
+
+	context("when the working directory contains a Gopkg.lock", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(workingDir, "Gopkg.lock"), nil, 0600)).To(Succeed())
+		})
+
+		it("returns a plan that provides and requires dep", func() {
+			result, err := detect(packit.DetectContext{
+				WorkingDir: workingDir,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Plan).To(Equal(packit.BuildPlan{
+				Provides: []packit.BuildPlanProvision{
+					{Name: "dep"},
+				},
+				Requires: []packit.BuildPlanRequirement{
+					{
+						Name: "dep",
+						Metadata: dep.BuildPlanMetadata{
+							Build: true,
+						},
+					},
+				},
+			}))
+		})
+	})
+
+	context("when BP_DEP_BUILD_FROM_SOURCE is true", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_DEP_BUILD_FROM_SOURCE", "true")).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_DEP_BUILD_FROM_SOURCE")).To(Succeed())
+		})
+
+		it("requires go at build time", func() {
+			result, err := detect(packit.DetectContext{
+				WorkingDir: workingDir,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Plan).To(Equal(packit.BuildPlan{
+				Provides: []packit.BuildPlanProvision{
+					{Name: "dep"},
+				},
+				Requires: []packit.BuildPlanRequirement{
+					{
+						Name: "go",
+						Metadata: dep.BuildPlanMetadata{
+							Build: true,
+						},
+					},
+				},
+			}))
+		})
+	}, spec.Sequential())
+
+	context("failure cases", func() {
+		context("when BP_DEP_BUILD_FROM_SOURCE is not a boolean", func() {
+			it.Before(func() {
+				Expect(os.Setenv("BP_DEP_BUILD_FROM_SOURCE", "not-a-bool")).To(Succeed())
+			})
+
+			it.After(func() {
+				Expect(os.Unsetenv("BP_DEP_BUILD_FROM_SOURCE")).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).To(MatchError(ContainSubstring("failed to parse BP_DEP_BUILD_FROM_SOURCE")))
+			})
+		}, spec.Sequential())
+
+		context("when the buildpack.yml cannot be parsed", func() {
+			it.Before(func() {
+				buildpackYMLParser.ParseVersionCall.Returns.Err = errors.New("failed to parse buildpack.yml")
+			})
+
+			it("returns an error", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).To(MatchError("failed to parse buildpack.yml"))
+			})
+		})
+
+		context("when the Gopkg.toml cannot be parsed", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "Gopkg.toml"), nil, 0600)).To(Succeed())
+				gopkgTomlParser.ParseVersionCall.Returns.Err = errors.New("failed to parse Gopkg.toml")
+			})
+
+			it("returns an error", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).To(MatchError("failed to parse Gopkg.toml"))
+			})
+		})
+
+		context("when the heroku metadata of the Gopkg.toml cannot be parsed", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "Gopkg.toml"), nil, 0600)).To(Succeed())
+				gopkgTomlParser.ParseHerokuMetadataCall.Returns.Error = errors.New("failed to parse Gopkg.toml")
+			})
+
+			it("returns an error", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).To(MatchError("failed to parse Gopkg.toml"))
+			})
+		})
+
+		context("when the Gopkg files cannot be stat'd", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "not-a-dir"), nil, 0600)).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: filepath.Join(workingDir, "not-a-dir"),
+				})
+				Expect(err).To(MatchError(ContainSubstring("not a directory")))
+			})
 		})
 	})
 }
@@ -0,0 +1,32 @@
+package fakes
+
+import "sync"
+
+type CheckProcess struct {
+	ExecuteCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			Gopath     string
+			ImportPath string
+			BinPath    string
+		}
+		Returns struct {
+			Error error
+		}
+		Stub func(string, string, string) error
+	}
+}
+
+func (f *CheckProcess) Execute(param1 string, param2 string, param3 string) error {
+	f.ExecuteCall.mutex.Lock()
+	defer f.ExecuteCall.mutex.Unlock()
+	f.ExecuteCall.CallCount++
+	f.ExecuteCall.Receives.Gopath = param1
+	f.ExecuteCall.Receives.ImportPath = param2
+	f.ExecuteCall.Receives.BinPath = param3
+	if f.ExecuteCall.Stub != nil {
+		return f.ExecuteCall.Stub(param1, param2, param3)
+	}
+	return f.ExecuteCall.Returns.Error
+}
@@ -0,0 +1,35 @@
+package fakes
+
+import (
+	"sync"
+
+	"github.com/paketo-buildpacks/dep"
+)
+
+type CredentialsResolver struct {
+	ResolveCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			PlatformDir string
+			HomeDir     string
+		}
+		Returns struct {
+			GitCredentials dep.GitCredentials
+			Error          error
+		}
+		Stub func(string, string) (dep.GitCredentials, error)
+	}
+}
+
+func (f *CredentialsResolver) Resolve(param1 string, param2 string) (dep.GitCredentials, error) {
+	f.ResolveCall.mutex.Lock()
+	defer f.ResolveCall.mutex.Unlock()
+	f.ResolveCall.CallCount++
+	f.ResolveCall.Receives.PlatformDir = param1
+	f.ResolveCall.Receives.HomeDir = param2
+	if f.ResolveCall.Stub != nil {
+		return f.ResolveCall.Stub(param1, param2)
+	}
+	return f.ResolveCall.Returns.GitCredentials, f.ResolveCall.Returns.Error
+}
@@ -0,0 +1,36 @@
+package fakes
+
+import "sync"
+
+type EnsureProcess struct {
+	ExecuteCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			Gopath     string
+			ImportPath string
+			BinPath    string
+			CachePath  string
+			Env        []string
+		}
+		Returns struct {
+			Error error
+		}
+		Stub func(string, string, string, string, []string) error
+	}
+}
+
+func (f *EnsureProcess) Execute(param1 string, param2 string, param3 string, param4 string, param5 []string) error {
+	f.ExecuteCall.mutex.Lock()
+	defer f.ExecuteCall.mutex.Unlock()
+	f.ExecuteCall.CallCount++
+	f.ExecuteCall.Receives.Gopath = param1
+	f.ExecuteCall.Receives.ImportPath = param2
+	f.ExecuteCall.Receives.BinPath = param3
+	f.ExecuteCall.Receives.CachePath = param4
+	f.ExecuteCall.Receives.Env = param5
+	if f.ExecuteCall.Stub != nil {
+		return f.ExecuteCall.Stub(param1, param2, param3, param4, param5)
+	}
+	return f.ExecuteCall.Returns.Error
+}
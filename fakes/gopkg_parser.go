@@ -0,0 +1,55 @@
+package fakes
+
+import (
+	"sync"
+
+	"github.com/paketo-buildpacks/dep"
+)
+
+type GopkgParser struct {
+	ParseHerokuMetadataCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			Path string
+		}
+		Returns struct {
+			HerokuMetadata dep.HerokuMetadata
+			Error          error
+		}
+		Stub func(string) (dep.HerokuMetadata, error)
+	}
+	ParseVersionCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			Path string
+		}
+		Returns struct {
+			Version string
+			Err     error
+		}
+		Stub func(string) (string, error)
+	}
+}
+
+func (f *GopkgParser) ParseHerokuMetadata(param1 string) (dep.HerokuMetadata, error) {
+	f.ParseHerokuMetadataCall.mutex.Lock()
+	defer f.ParseHerokuMetadataCall.mutex.Unlock()
+	f.ParseHerokuMetadataCall.CallCount++
+	f.ParseHerokuMetadataCall.Receives.Path = param1
+	if f.ParseHerokuMetadataCall.Stub != nil {
+		return f.ParseHerokuMetadataCall.Stub(param1)
+	}
+	return f.ParseHerokuMetadataCall.Returns.HerokuMetadata, f.ParseHerokuMetadataCall.Returns.Error
+}
+func (f *GopkgParser) ParseVersion(param1 string) (string, error) {
+	f.ParseVersionCall.mutex.Lock()
+	defer f.ParseVersionCall.mutex.Unlock()
+	f.ParseVersionCall.CallCount++
+	f.ParseVersionCall.Receives.Path = param1
+	if f.ParseVersionCall.Stub != nil {
+		return f.ParseVersionCall.Stub(param1)
+	}
+	return f.ParseVersionCall.Returns.Version, f.ParseVersionCall.Returns.Err
+}
@@ -0,0 +1,30 @@
+package fakes
+
+import "sync"
+
+type ImportPathResolver struct {
+	ResolveCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			WorkingDir string
+		}
+		Returns struct {
+			ImportPath string
+			Source     string
+			Err        error
+		}
+		Stub func(string) (string, string, error)
+	}
+}
+
+func (f *ImportPathResolver) Resolve(param1 string) (string, string, error) {
+	f.ResolveCall.mutex.Lock()
+	defer f.ResolveCall.mutex.Unlock()
+	f.ResolveCall.CallCount++
+	f.ResolveCall.Receives.WorkingDir = param1
+	if f.ResolveCall.Stub != nil {
+		return f.ResolveCall.Stub(param1)
+	}
+	return f.ResolveCall.Returns.ImportPath, f.ResolveCall.Returns.Source, f.ResolveCall.Returns.Err
+}
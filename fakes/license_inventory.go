@@ -0,0 +1,33 @@
+package fakes
+
+import (
+	"sync"
+
+	"github.com/paketo-buildpacks/dep"
+)
+
+type LicenseInventory struct {
+	CollectCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			WorkingDir string
+		}
+		Returns struct {
+			ProjectLicensesSlice []dep.ProjectLicenses
+			Error                error
+		}
+		Stub func(string) ([]dep.ProjectLicenses, error)
+	}
+}
+
+func (f *LicenseInventory) Collect(param1 string) ([]dep.ProjectLicenses, error) {
+	f.CollectCall.mutex.Lock()
+	defer f.CollectCall.mutex.Unlock()
+	f.CollectCall.CallCount++
+	f.CollectCall.Receives.WorkingDir = param1
+	if f.CollectCall.Stub != nil {
+		return f.CollectCall.Stub(param1)
+	}
+	return f.CollectCall.Returns.ProjectLicensesSlice, f.CollectCall.Returns.Error
+}
@@ -0,0 +1,37 @@
+package fakes
+
+import (
+	"sync"
+
+	"github.com/paketo-buildpacks/dep"
+)
+
+type ModuleMigrator struct {
+	MigrateCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			WorkingDir string
+			CacheDir   string
+			ModulePath string
+		}
+		Returns struct {
+			ModuleMigration dep.ModuleMigration
+			Error           error
+		}
+		Stub func(string, string, string) (dep.ModuleMigration, error)
+	}
+}
+
+func (f *ModuleMigrator) Migrate(param1 string, param2 string, param3 string) (dep.ModuleMigration, error) {
+	f.MigrateCall.mutex.Lock()
+	defer f.MigrateCall.mutex.Unlock()
+	f.MigrateCall.CallCount++
+	f.MigrateCall.Receives.WorkingDir = param1
+	f.MigrateCall.Receives.CacheDir = param2
+	f.MigrateCall.Receives.ModulePath = param3
+	if f.MigrateCall.Stub != nil {
+		return f.MigrateCall.Stub(param1, param2, param3)
+	}
+	return f.MigrateCall.Returns.ModuleMigration, f.MigrateCall.Returns.Error
+}
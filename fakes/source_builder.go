@@ -0,0 +1,62 @@
+package fakes
+
+import (
+	"sync"
+
+	"github.com/paketo-buildpacks/packit/v2/postal"
+)
+
+type SourceBuilder struct {
+	BuildCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			Dependency postal.Dependency
+			CnbPath    string
+			LayerPath  string
+		}
+		Returns struct {
+			Error error
+		}
+		Stub func(postal.Dependency, string, string) error
+	}
+	ResolveCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			Path    string
+			Id      string
+			Version string
+		}
+		Returns struct {
+			Dependency postal.Dependency
+			Error      error
+		}
+		Stub func(string, string, string) (postal.Dependency, error)
+	}
+}
+
+func (f *SourceBuilder) Build(param1 postal.Dependency, param2 string, param3 string) error {
+	f.BuildCall.mutex.Lock()
+	defer f.BuildCall.mutex.Unlock()
+	f.BuildCall.CallCount++
+	f.BuildCall.Receives.Dependency = param1
+	f.BuildCall.Receives.CnbPath = param2
+	f.BuildCall.Receives.LayerPath = param3
+	if f.BuildCall.Stub != nil {
+		return f.BuildCall.Stub(param1, param2, param3)
+	}
+	return f.BuildCall.Returns.Error
+}
+func (f *SourceBuilder) Resolve(param1 string, param2 string, param3 string) (postal.Dependency, error) {
+	f.ResolveCall.mutex.Lock()
+	defer f.ResolveCall.mutex.Unlock()
+	f.ResolveCall.CallCount++
+	f.ResolveCall.Receives.Path = param1
+	f.ResolveCall.Receives.Id = param2
+	f.ResolveCall.Receives.Version = param3
+	if f.ResolveCall.Stub != nil {
+		return f.ResolveCall.Stub(param1, param2, param3)
+	}
+	return f.ResolveCall.Returns.Dependency, f.ResolveCall.Returns.Error
+}
@@ -0,0 +1,79 @@
+package fakes
+
+import (
+	"sync"
+
+	"github.com/paketo-buildpacks/dep"
+)
+
+type SourceRewriter struct {
+	ResolveCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			PlatformDir string
+		}
+		Returns struct {
+			SourceRewriteSlice []dep.SourceRewrite
+			Error              error
+		}
+		Stub func(string) ([]dep.SourceRewrite, error)
+	}
+	WriteGitConfigCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			Path     string
+			Rewrites []dep.SourceRewrite
+		}
+		Returns struct {
+			Error error
+		}
+		Stub func(string, []dep.SourceRewrite) error
+	}
+	WriteNetrcCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			Path     string
+			Rewrites []dep.SourceRewrite
+		}
+		Returns struct {
+			Error error
+		}
+		Stub func(string, []dep.SourceRewrite) error
+	}
+}
+
+func (f *SourceRewriter) Resolve(param1 string) ([]dep.SourceRewrite, error) {
+	f.ResolveCall.mutex.Lock()
+	defer f.ResolveCall.mutex.Unlock()
+	f.ResolveCall.CallCount++
+	f.ResolveCall.Receives.PlatformDir = param1
+	if f.ResolveCall.Stub != nil {
+		return f.ResolveCall.Stub(param1)
+	}
+	return f.ResolveCall.Returns.SourceRewriteSlice, f.ResolveCall.Returns.Error
+}
+func (f *SourceRewriter) WriteGitConfig(param1 string, param2 []dep.SourceRewrite) error {
+	f.WriteGitConfigCall.mutex.Lock()
+	defer f.WriteGitConfigCall.mutex.Unlock()
+	f.WriteGitConfigCall.CallCount++
+	f.WriteGitConfigCall.Receives.Path = param1
+	f.WriteGitConfigCall.Receives.Rewrites = param2
+	if f.WriteGitConfigCall.Stub != nil {
+		return f.WriteGitConfigCall.Stub(param1, param2)
+	}
+	return f.WriteGitConfigCall.Returns.Error
+}
+func (f *SourceRewriter) WriteNetrc(param1 string, param2 []dep.SourceRewrite) error {
+	f.WriteNetrcCall.mutex.Lock()
+	defer f.WriteNetrcCall.mutex.Unlock()
+	f.WriteNetrcCall.CallCount++
+	f.WriteNetrcCall.Receives.Path = param1
+	f.WriteNetrcCall.Receives.Rewrites = param2
+	if f.WriteNetrcCall.Stub != nil {
+		return f.WriteNetrcCall.Stub(param1, param2)
+	}
+	return f.WriteNetrcCall.Returns.Error
+}
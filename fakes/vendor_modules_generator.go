@@ -0,0 +1,35 @@
+package fakes
+
+import (
+	"sync"
+
+	"github.com/paketo-buildpacks/dep"
+)
+
+type VendorModulesGenerator struct {
+	GenerateCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			WorkingDir string
+			ModulePath string
+		}
+		Returns struct {
+			VendorModules dep.VendorModules
+			Error         error
+		}
+		Stub func(string, string) (dep.VendorModules, error)
+	}
+}
+
+func (f *VendorModulesGenerator) Generate(param1 string, param2 string) (dep.VendorModules, error) {
+	f.GenerateCall.mutex.Lock()
+	defer f.GenerateCall.mutex.Unlock()
+	f.GenerateCall.CallCount++
+	f.GenerateCall.Receives.WorkingDir = param1
+	f.GenerateCall.Receives.ModulePath = param2
+	if f.GenerateCall.Stub != nil {
+		return f.GenerateCall.Stub(param1, param2)
+	}
+	return f.GenerateCall.Returns.VendorModules, f.GenerateCall.Returns.Error
+}
@@ -0,0 +1,32 @@
+package fakes
+
+import "sync"
+
+type VendorPruner struct {
+	PruneCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			WorkingDir string
+			VendorDir  string
+			Options    []string
+		}
+		Returns struct {
+			Error error
+		}
+		Stub func(string, string, []string) error
+	}
+}
+
+func (f *VendorPruner) Prune(param1 string, param2 string, param3 []string) error {
+	f.PruneCall.mutex.Lock()
+	defer f.PruneCall.mutex.Unlock()
+	f.PruneCall.CallCount++
+	f.PruneCall.Receives.WorkingDir = param1
+	f.PruneCall.Receives.VendorDir = param2
+	f.PruneCall.Receives.Options = param3
+	if f.PruneCall.Stub != nil {
+		return f.PruneCall.Stub(param1, param2, param3)
+	}
+	return f.PruneCall.Returns.Error
+}
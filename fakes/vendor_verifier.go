@@ -0,0 +1,33 @@
+package fakes
+
+import (
+	"sync"
+
+	"github.com/paketo-buildpacks/dep"
+)
+
+type VendorVerifier struct {
+	VerifyCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			WorkingDir string
+		}
+		Returns struct {
+			VendorChangeSlice []dep.VendorChange
+			Error             error
+		}
+		Stub func(string) ([]dep.VendorChange, error)
+	}
+}
+
+func (f *VendorVerifier) Verify(param1 string) ([]dep.VendorChange, error) {
+	f.VerifyCall.mutex.Lock()
+	defer f.VerifyCall.mutex.Unlock()
+	f.VerifyCall.CallCount++
+	f.VerifyCall.Receives.WorkingDir = param1
+	if f.VerifyCall.Stub != nil {
+		return f.VerifyCall.Stub(param1)
+	}
+	return f.VerifyCall.Returns.VendorChangeSlice, f.VerifyCall.Returns.Error
+}
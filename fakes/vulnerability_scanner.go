@@ -0,0 +1,35 @@
+package fakes
+
+import (
+	"sync"
+
+	"github.com/paketo-buildpacks/dep"
+)
+
+type VulnerabilityScanner struct {
+	ScanCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			WorkingDir  string
+			PlatformDir string
+		}
+		Returns struct {
+			VulnerabilityScan dep.VulnerabilityScan
+			Error             error
+		}
+		Stub func(string, string) (dep.VulnerabilityScan, error)
+	}
+}
+
+func (f *VulnerabilityScanner) Scan(param1 string, param2 string) (dep.VulnerabilityScan, error) {
+	f.ScanCall.mutex.Lock()
+	defer f.ScanCall.mutex.Unlock()
+	f.ScanCall.CallCount++
+	f.ScanCall.Receives.WorkingDir = param1
+	f.ScanCall.Receives.PlatformDir = param2
+	if f.ScanCall.Stub != nil {
+		return f.ScanCall.Stub(param1, param2)
+	}
+	return f.ScanCall.Returns.VulnerabilityScan, f.ScanCall.Returns.Error
+}
@@ -0,0 +1,213 @@
+package dep
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/paketo-buildpacks/packit/v2/servicebindings"
+)
+
+// GitCredentials describes how dep is given access to private repositories.
+type GitCredentials struct {
+	// Env holds the variables to add to the environment of dep ensure.
+	Env []string
+
+	// Bindings are the names of the bindings that the credentials were read
+	// from, so that they can be logged without revealing the credentials.
+	Bindings []string
+}
+
+type GitCredentialsResolver struct {
+	bindingResolver BindingResolver
+}
+
+func NewGitCredentialsResolver(bindingResolver BindingResolver) GitCredentialsResolver {
+	return GitCredentialsResolver{
+		bindingResolver: bindingResolver,
+	}
+}
+
+// Resolve reads the credentials for private repositories from the service
+// bindings in platformDir and writes them into homeDir, which becomes the HOME
+// of git when dep fetches sources. The homeDir must not be part of any layer,
+// so that the credentials do not end up in the image or the cache.
+//
+// A "git-credentials" binding either holds a "netrc" entry with the content
+// of a .netrc file, or a "username" and a "password" or "token" entry along
+// with an optional "host" entry that they apply to. An "ssh" binding holds an
+// "ssh-privatekey" entry and an optional "known_hosts" entry.
+func (r GitCredentialsResolver) Resolve(platformDir, homeDir string) (GitCredentials, error) {
+	var credentials GitCredentials
+
+	netrc, names, err := r.netrc(platformDir)
+	if err != nil {
+		return GitCredentials{}, err
+	}
+
+	if netrc != "" {
+		err = os.WriteFile(filepath.Join(homeDir, ".netrc"), []byte(netrc), 0600)
+		if err != nil {
+			return GitCredentials{}, fmt.Errorf("failed to write git credentials: %w", err)
+		}
+
+		credentials.Bindings = append(credentials.Bindings, names...)
+	}
+
+	sshCommand, names, err := r.ssh(platformDir, homeDir)
+	if err != nil {
+		return GitCredentials{}, err
+	}
+
+	if sshCommand != "" {
+		credentials.Env = append(credentials.Env, fmt.Sprintf("GIT_SSH_COMMAND=%s", sshCommand))
+		credentials.Bindings = append(credentials.Bindings, names...)
+	}
+
+	if len(credentials.Bindings) > 0 {
+		credentials.Env = append([]string{fmt.Sprintf("HOME=%s", homeDir)}, credentials.Env...)
+	}
+
+	return credentials, nil
+}
+
+func (r GitCredentialsResolver) netrc(platformDir string) (string, []string, error) {
+	bindings, err := r.bindingResolver.Resolve("git-credentials", "", platformDir)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to resolve 'git-credentials' binding: %w", err)
+	}
+	sortBindings(bindings)
+
+	var netrc strings.Builder
+	var names []string
+	for _, binding := range bindings {
+		if _, ok := binding.Entries["netrc"]; ok {
+			content, err := readBindingEntry(binding, "netrc")
+			if err != nil {
+				return "", nil, err
+			}
+
+			netrc.WriteString(strings.TrimSpace(content) + "\n")
+			names = append(names, binding.Name)
+			continue
+		}
+
+		username, err := readBindingEntry(binding, "username")
+		if err != nil {
+			return "", nil, err
+		}
+
+		secret := "password"
+		if _, ok := binding.Entries["token"]; ok {
+			secret = "token"
+		}
+
+		password, err := readBindingEntry(binding, secret)
+		if err != nil {
+			return "", nil, err
+		}
+
+		machine := "default"
+		if _, ok := binding.Entries["host"]; ok {
+			host, err := readBindingEntry(binding, "host")
+			if err != nil {
+				return "", nil, err
+			}
+
+			machine = fmt.Sprintf("machine %s", host)
+		}
+
+		fmt.Fprintf(&netrc, "%s login %s password %s\n", machine, username, password)
+		names = append(names, binding.Name)
+	}
+
+	return netrc.String(), names, nil
+}
+
+func (r GitCredentialsResolver) ssh(platformDir, homeDir string) (string, []string, error) {
+	bindings, err := r.bindingResolver.Resolve("ssh", "", platformDir)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to resolve 'ssh' binding: %w", err)
+	}
+	sortBindings(bindings)
+
+	if len(bindings) == 0 {
+		return "", nil, nil
+	}
+
+	sshDir := filepath.Join(homeDir, ".ssh")
+	err = os.MkdirAll(sshDir, 0700)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to write ssh credentials: %w", err)
+	}
+
+	var names []string
+	var knownHosts strings.Builder
+	args := []string{"ssh", "-F", "/dev/null", "-o", "IdentitiesOnly=yes"}
+	for i, binding := range bindings {
+		key, err := readBindingEntry(binding, "ssh-privatekey")
+		if err != nil {
+			return "", nil, err
+		}
+
+		keyPath := filepath.Join(sshDir, fmt.Sprintf("id_%d", i))
+		err = os.WriteFile(keyPath, []byte(strings.TrimSpace(key)+"\n"), 0600)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to write ssh credentials: %w", err)
+		}
+
+		args = append(args, "-i", keyPath)
+
+		if _, ok := binding.Entries["known_hosts"]; ok {
+			hosts, err := readBindingEntry(binding, "known_hosts")
+			if err != nil {
+				return "", nil, err
+			}
+
+			knownHosts.WriteString(strings.TrimSpace(hosts) + "\n")
+		}
+
+		names = append(names, binding.Name)
+	}
+
+	// Without known hosts, the host key of a server is trusted on first use,
+	// as there is no user that could confirm it during a build.
+	knownHostsPath := filepath.Join(sshDir, "known_hosts")
+	err = os.WriteFile(knownHostsPath, []byte(knownHosts.String()), 0600)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to write ssh credentials: %w", err)
+	}
+
+	strict := "accept-new"
+	if knownHosts.Len() > 0 {
+		strict = "yes"
+	}
+
+	args = append(args, "-o", fmt.Sprintf("UserKnownHostsFile=%s", knownHostsPath), "-o", fmt.Sprintf("StrictHostKeyChecking=%s", strict))
+
+	return strings.Join(args, " "), names, nil
+}
+
+func readBindingEntry(binding servicebindings.Binding, key string) (string, error) {
+	entry, ok := binding.Entries[key]
+	if !ok {
+		return "", fmt.Errorf("binding %q of type %q is missing the %q entry", binding.Name, binding.Type, key)
+	}
+
+	value, err := entry.ReadString()
+	if err != nil {
+		return "", fmt.Errorf("failed to read binding %q: %w", binding.Name, err)
+	}
+
+	return strings.TrimSpace(value), nil
+}
+
+// sortBindings orders bindings by name, so that credentials are written in
+// the same order on every build.
+func sortBindings(bindings []servicebindings.Binding) {
+	sort.Slice(bindings, func(i, j int) bool {
+		return bindings[i].Name < bindings[j].Name
+	})
+}
@@ -0,0 +1,233 @@
+package dep_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/dep"
+	"github.com/paketo-buildpacks/dep/fakes"
+	"github.com/paketo-buildpacks/packit/v2/servicebindings"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testGitCredentialsResolver(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		bindingDir      string
+		homeDir         string
+		bindings        map[string][]servicebindings.Binding
+		bindingResolver *fakes.BindingResolver
+		resolver        dep.GitCredentialsResolver
+	)
+
+	newBinding := func(name, typ string, entries map[string]string) servicebindings.Binding {
+		binding := servicebindings.Binding{
+			Name:    name,
+			Type:    typ,
+			Path:    filepath.Join(bindingDir, name),
+			Entries: map[string]*servicebindings.Entry{},
+		}
+		Expect(os.MkdirAll(binding.Path, os.ModePerm)).To(Succeed())
+
+		for key, value := range entries {
+			path := filepath.Join(binding.Path, key)
+			Expect(os.WriteFile(path, []byte(value), 0600)).To(Succeed())
+			binding.Entries[key] = servicebindings.NewEntry(path)
+		}
+
+		return binding
+	}
+
+	it.Before(func() {
+		var err error
+		bindingDir, err = os.MkdirTemp("", "binding")
+		Expect(err).NotTo(HaveOccurred())
+
+		homeDir, err = os.MkdirTemp("", "home")
+		Expect(err).NotTo(HaveOccurred())
+
+		bindings = map[string][]servicebindings.Binding{}
+		bindingResolver = &fakes.BindingResolver{}
+		bindingResolver.ResolveCall.Stub = func(typ, provider, platformDir string) ([]servicebindings.Binding, error) {
+			return bindings[typ], nil
+		}
+
+		resolver = dep.NewGitCredentialsResolver(bindingResolver)
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(bindingDir)).To(Succeed())
+		Expect(os.RemoveAll(homeDir)).To(Succeed())
+	})
+
+	context("Resolve", func() {
+		context("when no credentials are bound", func() {
+			it("returns no environment and writes nothing", func() {
+				credentials, err := resolver.Resolve("some-platform", homeDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(credentials).To(Equal(dep.GitCredentials{}))
+
+				Expect(bindingResolver.ResolveCall.Receives.PlatformDir).To(Equal("some-platform"))
+
+				entries, err := os.ReadDir(homeDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(entries).To(BeEmpty())
+			})
+		})
+
+		context("when a git-credentials binding holds a netrc", func() {
+			it.Before(func() {
+				bindings["git-credentials"] = []servicebindings.Binding{
+					newBinding("some-netrc", "git-credentials", map[string]string{
+						"netrc": "machine github.example.com login some-user password some-password\n",
+					}),
+				}
+			})
+
+			it("writes the netrc into the home directory", func() {
+				credentials, err := resolver.Resolve("some-platform", homeDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(credentials.Env).To(Equal([]string{fmt.Sprintf("HOME=%s", homeDir)}))
+				Expect(credentials.Bindings).To(Equal([]string{"some-netrc"}))
+
+				contents, err := os.ReadFile(filepath.Join(homeDir, ".netrc"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(contents)).To(Equal("machine github.example.com login some-user password some-password\n"))
+
+				info, err := os.Stat(filepath.Join(homeDir, ".netrc"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+			})
+		})
+
+		context("when git-credentials bindings hold a username and token", func() {
+			it.Before(func() {
+				bindings["git-credentials"] = []servicebindings.Binding{
+					newBinding("some-bitbucket", "git-credentials", map[string]string{
+						"username": "some-user\n",
+						"password": "some-password\n",
+					}),
+					newBinding("some-github", "git-credentials", map[string]string{
+						"host":     "github.example.com",
+						"username": "some-other-user",
+						"token":    "some-token",
+					}),
+				}
+			})
+
+			it("writes a netrc entry for each of them", func() {
+				credentials, err := resolver.Resolve("some-platform", homeDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(credentials.Bindings).To(Equal([]string{"some-bitbucket", "some-github"}))
+
+				contents, err := os.ReadFile(filepath.Join(homeDir, ".netrc"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(contents)).To(Equal(`default login some-user password some-password
+machine github.example.com login some-other-user password some-token
+`))
+			})
+		})
+
+		context("when an ssh binding is present", func() {
+			it.Before(func() {
+				bindings["ssh"] = []servicebindings.Binding{
+					newBinding("some-ssh", "ssh", map[string]string{
+						"ssh-privatekey": "some-private-key",
+						"known_hosts":    "bitbucket.example.com ssh-ed25519 some-host-key",
+					}),
+				}
+			})
+
+			it("writes the key and known hosts and configures ssh to use them", func() {
+				credentials, err := resolver.Resolve("some-platform", homeDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(credentials.Bindings).To(Equal([]string{"some-ssh"}))
+
+				sshDir := filepath.Join(homeDir, ".ssh")
+				Expect(credentials.Env).To(Equal([]string{
+					fmt.Sprintf("HOME=%s", homeDir),
+					fmt.Sprintf("GIT_SSH_COMMAND=ssh -F /dev/null -o IdentitiesOnly=yes -i %s -o UserKnownHostsFile=%s -o StrictHostKeyChecking=yes",
+						filepath.Join(sshDir, "id_0"), filepath.Join(sshDir, "known_hosts")),
+				}))
+
+				contents, err := os.ReadFile(filepath.Join(sshDir, "id_0"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(contents)).To(Equal("some-private-key\n"))
+
+				info, err := os.Stat(filepath.Join(sshDir, "id_0"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+				contents, err = os.ReadFile(filepath.Join(sshDir, "known_hosts"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(contents)).To(Equal("bitbucket.example.com ssh-ed25519 some-host-key\n"))
+			})
+
+			context("when the binding has no known hosts", func() {
+				it.Before(func() {
+					bindings["ssh"] = []servicebindings.Binding{
+						newBinding("some-ssh", "ssh", map[string]string{
+							"ssh-privatekey": "some-private-key",
+						}),
+					}
+				})
+
+				it("accepts new host keys", func() {
+					credentials, err := resolver.Resolve("some-platform", homeDir)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(credentials.Env).To(ContainElement(HaveSuffix("-o StrictHostKeyChecking=accept-new")))
+				})
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the git-credentials binding cannot be resolved", func() {
+				it.Before(func() {
+					bindingResolver.ResolveCall.Stub = nil
+					bindingResolver.ResolveCall.Returns.Error = errors.New("some-error")
+				})
+
+				it("returns an error", func() {
+					_, err := resolver.Resolve("some-platform", homeDir)
+					Expect(err).To(MatchError("failed to resolve 'git-credentials' binding: some-error"))
+				})
+			})
+
+			context("when a git-credentials binding has no username", func() {
+				it.Before(func() {
+					bindings["git-credentials"] = []servicebindings.Binding{
+						newBinding("some-github", "git-credentials", map[string]string{
+							"token": "some-token",
+						}),
+					}
+				})
+
+				it("returns an error", func() {
+					_, err := resolver.Resolve("some-platform", homeDir)
+					Expect(err).To(MatchError(`binding "some-github" of type "git-credentials" is missing the "username" entry`))
+				})
+			})
+
+			context("when an ssh binding has no private key", func() {
+				it.Before(func() {
+					bindings["ssh"] = []servicebindings.Binding{
+						newBinding("some-ssh", "ssh", map[string]string{
+							"known_hosts": "some-known-hosts",
+						}),
+					}
+				})
+
+				it("returns an error", func() {
+					_, err := resolver.Resolve("some-platform", homeDir)
+					Expect(err).To(MatchError(`binding "some-ssh" of type "ssh" is missing the "ssh-privatekey" entry`))
+				})
+			})
+		})
+	})
+}
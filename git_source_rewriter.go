@@ -0,0 +1,206 @@
+package dep
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+)
+
+// SourceRewrite redirects the sources of every project whose import path
+// starts with Prefix to the same path under URL.
+type SourceRewrite struct {
+	Prefix string
+	URL    string
+
+	// Username and Password are the credentials that were given in an http or
+	// https url. They are removed from URL, so that they are only written to
+	// a .netrc file and never into a git config.
+	Username string
+	Password string
+}
+
+type GitSourceRewriter struct {
+	bindingResolver BindingResolver
+}
+
+func NewGitSourceRewriter(bindingResolver BindingResolver) GitSourceRewriter {
+	return GitSourceRewriter{
+		bindingResolver: bindingResolver,
+	}
+}
+
+// Resolve returns the source rewrites that are configured in the
+// BP_DEP_SOURCE_REWRITES environment variable and in "dep-source-rewrites"
+// service bindings. Each rule has the form <prefix>=<url>. Rules in the
+// environment variable are separated by commas or whitespace, and each entry
+// of a binding holds one rule per line. When several rules are given for the
+// same prefix, the environment variable takes precedence over bindings.
+func (r GitSourceRewriter) Resolve(platformDir string) ([]SourceRewrite, error) {
+	var rewrites []SourceRewrite
+
+	if value, ok := os.LookupEnv("BP_DEP_SOURCE_REWRITES"); ok {
+		rules := strings.FieldsFunc(value, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t' || r == '\n'
+		})
+
+		for _, rule := range rules {
+			rewrite, err := parseSourceRewrite(rule, "BP_DEP_SOURCE_REWRITES")
+			if err != nil {
+				return nil, err
+			}
+
+			rewrites = appendSourceRewrite(rewrites, rewrite)
+		}
+	}
+
+	bindings, err := r.bindingResolver.Resolve("dep-source-rewrites", "", platformDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve 'dep-source-rewrites' binding: %w", err)
+	}
+	sortBindings(bindings)
+
+	for _, binding := range bindings {
+		var keys []string
+		for key := range binding.Entries {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			content, err := binding.Entries[key].ReadString()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read 'dep-source-rewrites' binding %q: %w", binding.Name, err)
+			}
+
+			scanner := bufio.NewScanner(strings.NewReader(content))
+			for scanner.Scan() {
+				rule := strings.TrimSpace(scanner.Text())
+				if rule == "" || strings.HasPrefix(rule, "#") {
+					continue
+				}
+
+				rewrite, err := parseSourceRewrite(rule, fmt.Sprintf("binding %q", binding.Name))
+				if err != nil {
+					return nil, err
+				}
+
+				rewrites = appendSourceRewrite(rewrites, rewrite)
+			}
+		}
+	}
+
+	return rewrites, nil
+}
+
+// WriteGitConfig writes a git config file to path that redirects the sources
+// of the given rewrites using url.<base>.insteadOf. dep fetches a project over
+// any of https, ssh, git, or http, so each of those remotes is redirected.
+func (r GitSourceRewriter) WriteGitConfig(path string, rewrites []SourceRewrite) error {
+	quoter := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+	var config strings.Builder
+	for _, rewrite := range rewrites {
+		fmt.Fprintf(&config, "[url \"%s\"]\n", quoter.Replace(rewrite.URL))
+		for _, remote := range []string{"https://", "ssh://git@", "git://", "http://"} {
+			fmt.Fprintf(&config, "\tinsteadOf = %s%s\n", remote, rewrite.Prefix)
+		}
+	}
+
+	err := os.WriteFile(path, []byte(config.String()), 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write git config: %w", err)
+	}
+
+	return nil
+}
+
+// WriteNetrc adds the credentials of the rewrites to the .netrc file at path.
+// They are written ahead of the entries that the file already has, so that a
+// default entry in it does not take precedence over them.
+func (r GitSourceRewriter) WriteNetrc(path string, rewrites []SourceRewrite) error {
+	var netrc strings.Builder
+	for _, rewrite := range rewrites {
+		if rewrite.Username == "" {
+			continue
+		}
+
+		u, err := url.Parse(rewrite.URL)
+		if err != nil {
+			return fmt.Errorf("failed to write git credentials: %w", err)
+		}
+
+		fmt.Fprintf(&netrc, "machine %s login %s", u.Hostname(), rewrite.Username)
+		if rewrite.Password != "" {
+			fmt.Fprintf(&netrc, " password %s", rewrite.Password)
+		}
+		netrc.WriteString("\n")
+	}
+
+	if netrc.Len() == 0 {
+		return nil
+	}
+
+	existing, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to write git credentials: %w", err)
+	}
+	netrc.Write(existing)
+
+	err = os.WriteFile(path, []byte(netrc.String()), 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write git credentials: %w", err)
+	}
+
+	return nil
+}
+
+func parseSourceRewrite(rule, source string) (SourceRewrite, error) {
+	prefix, target, found := strings.Cut(rule, "=")
+	prefix, target = strings.TrimSpace(prefix), strings.TrimSpace(target)
+	if !found || prefix == "" || target == "" {
+		return SourceRewrite{}, fmt.Errorf("invalid source rewrite %q in %s: expected <prefix>=<url>", rule, source)
+	}
+
+	if strings.Contains(prefix, "://") {
+		return SourceRewrite{}, fmt.Errorf("invalid source rewrite %q in %s: prefix must be an import path without a scheme", rule, source)
+	}
+
+	u, err := url.Parse(target)
+	if err != nil || u.Scheme == "" {
+		return SourceRewrite{}, fmt.Errorf("invalid source rewrite %q in %s: url must include a scheme", rule, source)
+	}
+
+	rewrite := SourceRewrite{Prefix: prefix, URL: target}
+
+	// Credentials can only be passed to git through a .netrc file for http and
+	// https. Other remotes keep their user, such as the git user of ssh.
+	if u.User != nil {
+		password, hasPassword := u.User.Password()
+		switch {
+		case u.Scheme == "http" || u.Scheme == "https":
+			rewrite.Username, rewrite.Password = u.User.Username(), password
+			u.User = nil
+			rewrite.URL = u.String()
+		case hasPassword:
+			return SourceRewrite{}, fmt.Errorf("invalid source rewrite for %q in %s: a password is only supported in http and https urls", prefix, source)
+		}
+	}
+
+	return rewrite, nil
+}
+
+// appendSourceRewrite adds the rewrite unless a rewrite for the same prefix
+// was already given.
+func appendSourceRewrite(rewrites []SourceRewrite, rewrite SourceRewrite) []SourceRewrite {
+	for _, existing := range rewrites {
+		if existing.Prefix == rewrite.Prefix {
+			return rewrites
+		}
+	}
+
+	return append(rewrites, rewrite)
+}
@@ -0,0 +1,46 @@
+package dep
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/BurntSushi/toml"
+)
+
+// LockedProject is a [[projects]] entry of a Gopkg.lock file.
+type LockedProject struct {
+	Name      string   `toml:"name"`
+	Branch    string   `toml:"branch"`
+	Revision  string   `toml:"revision"`
+	Version   string   `toml:"version"`
+	Source    string   `toml:"source"`
+	Digest    string   `toml:"digest"`
+	Packages  []string `toml:"packages"`
+	PruneOpts string   `toml:"pruneopts"`
+}
+
+type GopkgLockParser struct{}
+
+func NewGopkgLockParser() GopkgLockParser {
+	return GopkgLockParser{}
+}
+
+// Parse returns the projects locked in the Gopkg.lock file at path. It returns
+// no projects when the file does not exist.
+func (p GopkgLockParser) Parse(path string) ([]LockedProject, error) {
+	var lock struct {
+		Projects []LockedProject `toml:"projects"`
+	}
+
+	_, err := toml.DecodeFile(path, &lock)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to parse Gopkg.lock: %w", err)
+	}
+
+	return lock.Projects, nil
+}
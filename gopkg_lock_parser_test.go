@@ -0,0 +1,102 @@
+package dep_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/dep"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testGopkgLockParser(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+		parser     dep.GopkgLockParser
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = os.MkdirTemp("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+
+		parser = dep.NewGopkgLockParser()
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	context("Parse", func() {
+		it.Before(func() {
+			err := os.WriteFile(filepath.Join(workingDir, "Gopkg.lock"), []byte(`
+[[projects]]
+  branch = "master"
+  digest = "1:some-digest"
+  name = "github.com/ZiCog/shiny-thing"
+  packages = ["foo"]
+  pruneopts = "UT"
+  revision = "some-revision"
+
+[[projects]]
+  name = "github.com/pkg/errors"
+  packages = ["."]
+  revision = "some-other-revision"
+  source = "https://github.com/some-fork/errors"
+  version = "v0.8.1"
+
+[solve-meta]
+  analyzer-name = "dep"
+  analyzer-version = 1
+`), 0600)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it("returns the locked projects", func() {
+			projects, err := parser.Parse(filepath.Join(workingDir, "Gopkg.lock"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(projects).To(Equal([]dep.LockedProject{
+				{
+					Name:      "github.com/ZiCog/shiny-thing",
+					Branch:    "master",
+					Revision:  "some-revision",
+					Digest:    "1:some-digest",
+					Packages:  []string{"foo"},
+					PruneOpts: "UT",
+				},
+				{
+					Name:     "github.com/pkg/errors",
+					Revision: "some-other-revision",
+					Version:  "v0.8.1",
+					Source:   "https://github.com/some-fork/errors",
+					Packages: []string{"."},
+				},
+			}))
+		})
+
+		context("when the Gopkg.lock file does not exist", func() {
+			it("returns no projects", func() {
+				projects, err := parser.Parse(filepath.Join(workingDir, "missing.lock"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(projects).To(BeEmpty())
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the Gopkg.lock file cannot be parsed", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "Gopkg.lock"), []byte("%%%"), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := parser.Parse(filepath.Join(workingDir, "Gopkg.lock"))
+					Expect(err).To(MatchError(ContainSubstring("failed to parse Gopkg.lock")))
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,103 @@
+package dep
+
+import (
+	"net/url"
+	"path/filepath"
+
+	"github.com/anchore/syft/syft/pkg"
+	syftsbom "github.com/anchore/syft/syft/sbom"
+	"github.com/anchore/syft/syft/source"
+	"github.com/paketo-buildpacks/packit/v2/sbom"
+)
+
+type GopkgLockSBOMGenerator struct {
+	parser   GopkgLockParser
+	licenses VendorLicenseInventory
+}
+
+func NewGopkgLockSBOMGenerator() GopkgLockSBOMGenerator {
+	return GopkgLockSBOMGenerator{
+		parser:   NewGopkgLockParser(),
+		licenses: NewVendorLicenseInventory(),
+	}
+}
+
+// Generate returns an SBOM with a golang package for every project locked in
+// the Gopkg.lock in workingDir. The packages list the licenses found in the
+// vendored copy of their project.
+func (g GopkgLockSBOMGenerator) Generate(workingDir string) (sbom.SBOM, error) {
+	path := filepath.Join(workingDir, GopkgLock)
+
+	projects, err := g.parser.Parse(path)
+	if err != nil {
+		return sbom.SBOM{}, err
+	}
+
+	inventory, err := g.licenses.Collect(workingDir)
+	if err != nil {
+		return sbom.SBOM{}, err
+	}
+
+	licenses := map[string][]string{}
+	for _, project := range inventory {
+		licenses[project.Project] = project.Licenses
+	}
+
+	var packages []pkg.Package
+	for _, project := range projects {
+		version := project.Version
+		if version == "" {
+			version = project.Revision
+		}
+
+		packages = append(packages, pkg.Package{
+			Name:      project.Name,
+			Version:   version,
+			FoundBy:   "dep-buildpack",
+			Locations: source.NewLocationSet(source.NewLocation(GopkgLock)),
+			Language:  pkg.Go,
+			Type:      pkg.GoModulePkg,
+			Licenses:  licenses[project.Name],
+			PURL:      golangPURL(project, version),
+		})
+	}
+
+	return sbom.NewSBOM(syftsbom.SBOM{
+		Artifacts: syftsbom.Artifacts{
+			PackageCatalog: pkg.NewCatalog(packages...),
+		},
+		Source: source.Metadata{
+			Scheme: source.DirectoryScheme,
+			Path:   workingDir,
+		},
+	}), nil
+}
+
+// golangPURL returns the package URL of a locked project. The revision,
+// branch and alternate source of the project are recorded as qualifiers so
+// that projects that are not pinned to a release can still be identified.
+func golangPURL(project LockedProject, version string) string {
+	qualifiers := url.Values{}
+	if project.Revision != "" {
+		qualifiers.Set("revision", project.Revision)
+	}
+
+	if project.Branch != "" {
+		qualifiers.Set("branch", project.Branch)
+	}
+
+	if project.Source != "" {
+		qualifiers.Set("vcs_url", project.Source)
+	}
+
+	purl := "pkg:golang/" + project.Name
+	if version != "" {
+		purl += "@" + url.PathEscape(version)
+	}
+
+	if len(qualifiers) > 0 {
+		purl += "?" + qualifiers.Encode()
+	}
+
+	return purl
+}
@@ -0,0 +1,109 @@
+package dep_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/dep"
+	"github.com/paketo-buildpacks/packit/v2/sbom"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testGopkgLockSBOMGenerator(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+		generator  dep.GopkgLockSBOMGenerator
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = os.MkdirTemp("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = os.WriteFile(filepath.Join(workingDir, "Gopkg.lock"), []byte(`
+[[projects]]
+  branch = "master"
+  name = "github.com/ZiCog/shiny-thing"
+  packages = ["foo"]
+  revision = "some-revision"
+
+[[projects]]
+  name = "github.com/pkg/errors"
+  packages = ["."]
+  revision = "some-other-revision"
+  source = "https://github.com/some-fork/errors"
+  version = "v0.8.1"
+`), 0600)
+		Expect(err).NotTo(HaveOccurred())
+
+		generator = dep.NewGopkgLockSBOMGenerator()
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	context("Generate", func() {
+		it("describes every locked project as a golang package", func() {
+			content, err := generator.Generate(workingDir)
+			Expect(err).NotTo(HaveOccurred())
+
+			cdx, err := io.ReadAll(sbom.NewFormattedReader(content, sbom.CycloneDXFormat))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(cdx)).To(ContainSubstring(`"name": "github.com/ZiCog/shiny-thing"`))
+			Expect(string(cdx)).To(ContainSubstring(`"version": "some-revision"`))
+			Expect(string(cdx)).To(ContainSubstring(`"purl": "pkg:golang/github.com/ZiCog/shiny-thing@some-revision?branch=master\u0026revision=some-revision"`))
+			Expect(string(cdx)).To(ContainSubstring(`"name": "github.com/pkg/errors"`))
+			Expect(string(cdx)).To(ContainSubstring(`"version": "v0.8.1"`))
+			Expect(string(cdx)).To(ContainSubstring(`"purl": "pkg:golang/github.com/pkg/errors@v0.8.1?revision=some-other-revision\u0026vcs_url=https%3A%2F%2Fgithub.com%2Fsome-fork%2Ferrors"`))
+
+			spdx, err := io.ReadAll(sbom.NewFormattedReader(content, sbom.SPDXFormat))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(spdx)).To(ContainSubstring(`"name": "github.com/pkg/errors"`))
+
+			syft, err := io.ReadAll(sbom.NewFormattedReader(content, sbom.SyftFormat))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(syft)).To(ContainSubstring(`"name": "github.com/pkg/errors"`))
+		})
+
+		context("when the projects are vendored", func() {
+			it.Before(func() {
+				path := filepath.Join(workingDir, "vendor", "github.com", "pkg", "errors")
+				Expect(os.MkdirAll(path, os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(path, "LICENSE"), []byte("SPDX-License-Identifier: BSD-2-Clause\n"), 0600)).To(Succeed())
+			})
+
+			it("lists the licenses of the vendored projects", func() {
+				content, err := generator.Generate(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+
+				cdx, err := io.ReadAll(sbom.NewFormattedReader(content, sbom.CycloneDXFormat))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(cdx)).To(ContainSubstring(`"id": "BSD-2-Clause"`))
+
+				syft, err := io.ReadAll(sbom.NewFormattedReader(content, sbom.SyftFormat))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(syft)).To(MatchRegexp(`"licenses": \[\s*"BSD-2-Clause"\s*\]`))
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the Gopkg.lock cannot be parsed", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "Gopkg.lock"), []byte("%%%"), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := generator.Generate(workingDir)
+					Expect(err).To(MatchError(ContainSubstring("failed to parse Gopkg.lock")))
+				})
+			})
+		})
+	})
+}
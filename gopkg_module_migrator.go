@@ -0,0 +1,298 @@
+package dep
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/paketo-buildpacks/packit/v2/pexec"
+)
+
+// ModuleMigration is the Go modules equivalent of the Gopkg.toml and
+// Gopkg.lock of an app.
+type ModuleMigration struct {
+	GoMod string
+
+	// Untranslatable lists every rule of the Gopkg files that has no
+	// equivalent in go.mod, along with how it was handled.
+	Untranslatable []string
+
+	// Unresolvable lists the locked projects that are not required at a
+	// version the go command can resolve, as their revision is not a git
+	// commit or its commit time is unknown.
+	Unresolvable []string
+}
+
+// minimumVersionConstraint matches the dep version constraints that only set
+// a lower bound within a major version, which is what a go.mod require
+// expresses. dep treats a bare version as a caret range.
+var minimumVersionConstraint = regexp.MustCompile(`^(\^|>=)?\s*v?\d+(\.\d+){0,2}(-[0-9A-Za-z.-]+)?$`)
+
+// majorVersionSuffix matches the /vN suffix of a module path that has a
+// major version of 2 or higher.
+var majorVersionSuffix = regexp.MustCompile(`/v([2-9]|[1-9][0-9]+)$`)
+
+// gopkgInMajorVersion matches the .vN suffix of a gopkg.in module path, which
+// selects its major version, including v0 and v1.
+var gopkgInMajorVersion = regexp.MustCompile(`^gopkg\.in/([^/]+/)?[^/.]+\.v(0|[1-9][0-9]*)(-unstable)?$`)
+
+// sourceCacheSanitizer turns a source URL into the name of its directory in
+// the sources of the dep cache, in the same way as dep does.
+var sourceCacheSanitizer = strings.NewReplacer("-", "--", ":", "-", "/", "-", "+", "-")
+
+type GopkgModuleMigrator struct {
+	git        Executable
+	tomlParser GopkgTomlParser
+	lockParser GopkgLockParser
+}
+
+func NewGopkgModuleMigrator(git Executable) GopkgModuleMigrator {
+	return GopkgModuleMigrator{
+		git:        git,
+		tomlParser: NewGopkgTomlParser(),
+		lockParser: NewGopkgLockParser(),
+	}
+}
+
+// Migrate translates the Gopkg.toml and Gopkg.lock in workingDir into a go.mod
+// for the module at modulePath. Every locked project is required at its locked
+// version, or at a pseudo-version of its locked revision when it is not locked
+// to a semantic version, and projects with an alternate source are replaced by
+// it. The commit time of a pseudo-version is looked up in the git sources that
+// dep fetched into cacheDir. No go.sum is generated, as the checksums of
+// modules can only be calculated from the modules themselves.
+func (m GopkgModuleMigrator) Migrate(workingDir, cacheDir, modulePath string) (ModuleMigration, error) {
+	heroku, err := m.tomlParser.ParseHerokuMetadata(filepath.Join(workingDir, GopkgToml))
+	if err != nil {
+		return ModuleMigration{}, err
+	}
+
+	constraints, err := m.tomlParser.ParseConstraints(filepath.Join(workingDir, GopkgToml))
+	if err != nil {
+		return ModuleMigration{}, err
+	}
+
+	projects, err := m.lockParser.Parse(filepath.Join(workingDir, GopkgLock))
+	if err != nil {
+		return ModuleMigration{}, err
+	}
+
+	var migration ModuleMigration
+	var requires, replaces []string
+	versions := map[string]string{}
+	for _, project := range projects {
+		version, ok := semanticModuleVersion(project)
+		if !ok {
+			commitTime, known := m.commitTime(cacheDir, project)
+
+			version, ok = pseudoModuleVersion(project, commitTime)
+			if !ok {
+				migration.Unresolvable = append(migration.Unresolvable, project.Name)
+				migration.Untranslatable = append(migration.Untranslatable,
+					fmt.Sprintf("%s: revision %q is not a git commit and was dropped", project.Name, project.Revision))
+				continue
+			}
+
+			reason := "revision is not a version"
+			switch {
+			case project.Version != "":
+				reason = fmt.Sprintf("version %q is not a semantic version", project.Version)
+			case project.Branch != "":
+				reason = fmt.Sprintf("branch %q cannot be tracked in go.mod", project.Branch)
+			}
+
+			problem := fmt.Sprintf("%s: %s, required at %s", project.Name, reason, version)
+			if !known {
+				migration.Unresolvable = append(migration.Unresolvable, project.Name)
+				problem = fmt.Sprintf("%s; its commit time is not in the dep cache, run `go get %s@%s` to correct it", problem, project.Name, project.Revision)
+			}
+
+			migration.Untranslatable = append(migration.Untranslatable, problem)
+		}
+
+		versions[project.Name] = version
+		requires = append(requires, fmt.Sprintf("%s %s", project.Name, version))
+
+		if project.Source != "" {
+			source := importPathFromRemote(project.Source)
+			if source == "" {
+				migration.Untranslatable = append(migration.Untranslatable,
+					fmt.Sprintf("%s: source %q cannot be expressed as a module path and was dropped", project.Name, project.Source))
+				continue
+			}
+
+			replaces = append(replaces, fmt.Sprintf("%s => %s %s", project.Name, source, version))
+		}
+	}
+
+	locked := map[string]LockedProject{}
+	for _, project := range projects {
+		locked[project.Name] = project
+	}
+
+	for _, rule := range []struct {
+		kind        string
+		constraints []ProjectConstraint
+	}{
+		{kind: "constraint", constraints: constraints.Constraints},
+		{kind: "override", constraints: constraints.Overrides},
+	} {
+		for _, constraint := range rule.constraints {
+			project, ok := locked[constraint.Name]
+			if !ok {
+				migration.Untranslatable = append(migration.Untranslatable,
+					fmt.Sprintf("%s: %s is not used by any locked project and was dropped", constraint.Name, rule.kind))
+				continue
+			}
+
+			if constraint.Version != "" && constraint.Version != "*" && !minimumVersionConstraint.MatchString(strings.TrimSpace(constraint.Version)) {
+				migration.Untranslatable = append(migration.Untranslatable,
+					fmt.Sprintf("%s: %s %q cannot be expressed in go.mod, which only records the minimum version %s", constraint.Name, rule.kind, constraint.Version, versions[project.Name]))
+			}
+		}
+	}
+
+	for _, ignored := range constraints.Ignored {
+		migration.Untranslatable = append(migration.Untranslatable,
+			fmt.Sprintf("%s: ignored packages cannot be expressed in go.mod and were dropped", ignored))
+	}
+
+	var goMod strings.Builder
+	fmt.Fprintf(&goMod, "module %s\n", modulePath)
+
+	if version := goDirective(heroku.GoVersion); version != "" {
+		fmt.Fprintf(&goMod, "\ngo %s\n", version)
+	}
+
+	if len(requires) > 0 {
+		goMod.WriteString("\nrequire (\n")
+		for _, require := range requires {
+			fmt.Fprintf(&goMod, "\t%s\n", require)
+		}
+		goMod.WriteString(")\n")
+	}
+
+	if len(replaces) > 0 {
+		goMod.WriteString("\nreplace (\n")
+		for _, replace := range replaces {
+			fmt.Fprintf(&goMod, "\t%s\n", replace)
+		}
+		goMod.WriteString(")\n")
+	}
+
+	migration.GoMod = goMod.String()
+
+	return migration, nil
+}
+
+// semanticModuleVersion returns the module version of a project that is locked
+// to a semantic version. Projects with a major version of 2 or higher that do
+// not have a major version in their import path are marked as incompatible.
+func semanticModuleVersion(project LockedProject) (string, bool) {
+	version, err := semver.StrictNewVersion(strings.TrimPrefix(project.Version, "v"))
+	if err != nil {
+		return "", false
+	}
+
+	result := "v" + version.String()
+
+	if _, ok := pathMajorVersion(project.Name); !ok && version.Major() >= 2 {
+		result += "+incompatible"
+	}
+
+	return result, true
+}
+
+// pathMajorVersion returns the major version that the import path of a module
+// selects, either with a /vN suffix or with the .vN suffix of gopkg.in.
+func pathMajorVersion(modulePath string) (uint64, bool) {
+	var digits string
+	if match := majorVersionSuffix.FindStringSubmatch(modulePath); match != nil {
+		digits = match[1]
+	} else if match := gopkgInMajorVersion.FindStringSubmatch(modulePath); match != nil {
+		digits = match[2]
+	} else {
+		return 0, false
+	}
+
+	major, err := strconv.ParseUint(digits, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return major, true
+}
+
+// goDirective returns the language version for the go directive of a go.mod
+// from a Go version such as "go1.12.5".
+func goDirective(goVersion string) string {
+	version, err := semver.NewVersion(strings.TrimPrefix(goVersion, "go"))
+	if err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%d.%d", version.Major(), version.Minor())
+}
+
+// pseudoModuleVersion returns a pseudo-version for the locked revision of a
+// project that was committed at commitTime, with the major version of its
+// import path. Gopkg.lock does not record when a revision was committed, so
+// without a commit time the pseudo-version has the zero time, which the go
+// command cannot resolve.
+func pseudoModuleVersion(project LockedProject, commitTime time.Time) (string, bool) {
+	if !gitRevision.MatchString(project.Revision) {
+		return "", false
+	}
+
+	major, _ := pathMajorVersion(project.Name)
+
+	return fmt.Sprintf("v%d.0.0-%s-%s", major, commitTime.UTC().Format("20060102150405"), project.Revision[:12]), true
+}
+
+// commitTime returns when the locked revision of a project was committed,
+// according to the git source that dep fetched for it into cacheDir. dep names
+// that source after its URL, which for a project without an alternate source
+// is its import path with a scheme in front.
+func (m GopkgModuleMigrator) commitTime(cacheDir string, project LockedProject) (time.Time, bool) {
+	entries, err := os.ReadDir(filepath.Join(cacheDir, "sources"))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	source := project.Source
+	if source == "" {
+		source = project.Name
+	}
+	name := sourceCacheSanitizer.Replace(source)
+
+	for _, entry := range entries {
+		if !entry.IsDir() || (entry.Name() != name && !strings.HasSuffix(entry.Name(), "-"+name)) {
+			continue
+		}
+
+		buffer := bytes.NewBuffer(nil)
+		err := m.git.Execute(pexec.Execution{
+			Args:   []string{"show", "--no-patch", "--format=%ct", project.Revision},
+			Dir:    filepath.Join(cacheDir, "sources", entry.Name()),
+			Stdout: buffer,
+		})
+		if err != nil {
+			continue
+		}
+
+		seconds, err := strconv.ParseInt(strings.TrimSpace(buffer.String()), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		return time.Unix(seconds, 0), true
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,287 @@
+package dep_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/dep"
+	"github.com/paketo-buildpacks/dep/fakes"
+	"github.com/paketo-buildpacks/packit/v2/pexec"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testGopkgModuleMigrator(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+		cacheDir   string
+		git        *fakes.Executable
+		executions []pexec.Execution
+		migrator   dep.GopkgModuleMigrator
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = os.MkdirTemp("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = os.WriteFile(filepath.Join(workingDir, "Gopkg.toml"), []byte(`
+ignored = ["github.com/some-org/some-ignored-repo"]
+
+[[constraint]]
+  name = "github.com/some-org/some-repo"
+  version = "1.2.0"
+
+[[constraint]]
+  name = "github.com/some-org/pinned-repo"
+  version = "~1.0.0"
+
+[[constraint]]
+  name = "github.com/some-org/unused-repo"
+  version = "^1.0.0"
+
+[[override]]
+  name = "github.com/some-org/forked-repo"
+  version = ">=2.0.0, <3.0.0"
+
+[metadata.heroku]
+  go-version = "go1.12.5"
+`), 0600)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = os.WriteFile(filepath.Join(workingDir, "Gopkg.lock"), []byte(`
+[[projects]]
+  branch = "main"
+  name = "github.com/some-org/branch-repo"
+  packages = ["."]
+  revision = "1111111111111111111111111111111111111111"
+
+[[projects]]
+  name = "github.com/some-org/forked-repo"
+  packages = ["."]
+  revision = "2222222222222222222222222222222222222222"
+  source = "git@git.example.com:some-fork/forked-repo.git"
+  version = "v2.1.0"
+
+[[projects]]
+  name = "github.com/some-org/pinned-repo"
+  packages = ["."]
+  revision = "3333333333333333333333333333333333333333"
+  version = "1.0.3"
+
+[[projects]]
+  name = "github.com/some-org/revision-repo"
+  packages = ["."]
+  revision = "4444444444444444444444444444444444444444"
+
+[[projects]]
+  name = "github.com/some-org/some-repo"
+  packages = ["."]
+  revision = "5555555555555555555555555555555555555555"
+  version = "v1.4.0"
+
+[[projects]]
+  name = "github.com/some-org/some-repo/v3"
+  packages = ["."]
+  revision = "7777777777777777777777777777777777777777"
+
+[[projects]]
+  name = "github.com/some-org/svn-repo"
+  packages = ["."]
+  revision = "1234"
+
+[[projects]]
+  name = "github.com/some-org/tagged-repo"
+  packages = ["."]
+  revision = "6666666666666666666666666666666666666666"
+  version = "release-1"
+`), 0600)
+		Expect(err).NotTo(HaveOccurred())
+
+		cacheDir, err = os.MkdirTemp("", "cache-dir")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.MkdirAll(filepath.Join(cacheDir, "sources", "https---github.com-some--org-branch--repo"), os.ModePerm)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(cacheDir, "sources", "https---github.com-some--org-revision--repo"), os.ModePerm)).To(Succeed())
+
+		executions = nil
+		git = &fakes.Executable{}
+		git.ExecuteCall.Stub = func(execution pexec.Execution) error {
+			executions = append(executions, execution)
+
+			// Only the branch is fetched, the locked revision of the other repo is
+			// missing from its source.
+			if filepath.Base(execution.Dir) != "https---github.com-some--org-branch--repo" {
+				return errors.New("unknown revision")
+			}
+
+			_, err := fmt.Fprintln(execution.Stdout, "1577934245")
+			return err
+		}
+
+		migrator = dep.NewGopkgModuleMigrator(git)
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+		Expect(os.RemoveAll(cacheDir)).To(Succeed())
+	})
+
+	context("Migrate", func() {
+		it("translates the Gopkg files into a go.mod", func() {
+			migration, err := migrator.Migrate(workingDir, cacheDir, "github.com/some-org/some-app")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(migration.GoMod).To(Equal(`module github.com/some-org/some-app
+
+go 1.12
+
+require (
+	github.com/some-org/branch-repo v0.0.0-20200102030405-111111111111
+	github.com/some-org/forked-repo v2.1.0+incompatible
+	github.com/some-org/pinned-repo v1.0.3
+	github.com/some-org/revision-repo v0.0.0-00010101000000-444444444444
+	github.com/some-org/some-repo v1.4.0
+	github.com/some-org/some-repo/v3 v3.0.0-00010101000000-777777777777
+	github.com/some-org/tagged-repo v0.0.0-00010101000000-666666666666
+)
+
+replace (
+	github.com/some-org/forked-repo => git.example.com/some-fork/forked-repo v2.1.0+incompatible
+)
+`))
+		})
+
+		it("looks up the commit times of revisions in the dep cache", func() {
+			_, err := migrator.Migrate(workingDir, cacheDir, "github.com/some-org/some-app")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executions).To(HaveLen(2))
+			Expect(executions[0].Args).To(Equal([]string{"show", "--no-patch", "--format=%ct", "1111111111111111111111111111111111111111"}))
+			Expect(executions[0].Dir).To(Equal(filepath.Join(cacheDir, "sources", "https---github.com-some--org-branch--repo")))
+			Expect(executions[1].Args).To(Equal([]string{"show", "--no-patch", "--format=%ct", "4444444444444444444444444444444444444444"}))
+			Expect(executions[1].Dir).To(Equal(filepath.Join(cacheDir, "sources", "https---github.com-some--org-revision--repo")))
+		})
+
+		it("lists the projects that have no version Go can resolve", func() {
+			migration, err := migrator.Migrate(workingDir, cacheDir, "github.com/some-org/some-app")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(migration.Unresolvable).To(Equal([]string{
+				"github.com/some-org/revision-repo",
+				"github.com/some-org/some-repo/v3",
+				"github.com/some-org/svn-repo",
+				"github.com/some-org/tagged-repo",
+			}))
+		})
+
+		it("lists everything that cannot be translated", func() {
+			migration, err := migrator.Migrate(workingDir, cacheDir, "github.com/some-org/some-app")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(migration.Untranslatable).To(Equal([]string{
+				`github.com/some-org/branch-repo: branch "main" cannot be tracked in go.mod, required at v0.0.0-20200102030405-111111111111`,
+				"github.com/some-org/revision-repo: revision is not a version, required at v0.0.0-00010101000000-444444444444; its commit time is not in the dep cache, run `go get github.com/some-org/revision-repo@4444444444444444444444444444444444444444` to correct it",
+				"github.com/some-org/some-repo/v3: revision is not a version, required at v3.0.0-00010101000000-777777777777; its commit time is not in the dep cache, run `go get github.com/some-org/some-repo/v3@7777777777777777777777777777777777777777` to correct it",
+				`github.com/some-org/svn-repo: revision "1234" is not a git commit and was dropped`,
+				"github.com/some-org/tagged-repo: version \"release-1\" is not a semantic version, required at v0.0.0-00010101000000-666666666666; its commit time is not in the dep cache, run `go get github.com/some-org/tagged-repo@6666666666666666666666666666666666666666` to correct it",
+				`github.com/some-org/pinned-repo: constraint "~1.0.0" cannot be expressed in go.mod, which only records the minimum version v1.0.3`,
+				`github.com/some-org/unused-repo: constraint is not used by any locked project and was dropped`,
+				`github.com/some-org/forked-repo: override ">=2.0.0, <3.0.0" cannot be expressed in go.mod, which only records the minimum version v2.1.0+incompatible`,
+				`github.com/some-org/some-ignored-repo: ignored packages cannot be expressed in go.mod and were dropped`,
+			}))
+		})
+
+		context("when projects are imported from gopkg.in", func() {
+			it.Before(func() {
+				err := os.WriteFile(filepath.Join(workingDir, "Gopkg.lock"), []byte(`
+[[projects]]
+  name = "gopkg.in/check.v1"
+  packages = ["."]
+  revision = "1111111111111111111111111111111111111111"
+  version = "v1.0.0"
+
+[[projects]]
+  name = "gopkg.in/go-playground/validator.v9"
+  packages = ["."]
+  revision = "3333333333333333333333333333333333333333"
+  version = "v9.31.0"
+
+[[projects]]
+  branch = "v2"
+  name = "gopkg.in/yaml.v2"
+  packages = ["."]
+  revision = "4444444444444444444444444444444444444444"
+`), 0600)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(os.MkdirAll(filepath.Join(cacheDir, "sources", "https---gopkg.in-yaml.v2"), os.ModePerm)).To(Succeed())
+				git.ExecuteCall.Stub = func(execution pexec.Execution) error {
+					_, err := fmt.Fprintln(execution.Stdout, "1577934245")
+					return err
+				}
+			})
+
+			it("requires them at the major version of their import path", func() {
+				migration, err := migrator.Migrate(workingDir, cacheDir, "app")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(migration.GoMod).To(Equal(`module app
+
+go 1.12
+
+require (
+	gopkg.in/check.v1 v1.0.0
+	gopkg.in/go-playground/validator.v9 v9.31.0
+	gopkg.in/yaml.v2 v2.0.0-20200102030405-444444444444
+)
+`))
+				Expect(migration.Unresolvable).To(BeEmpty())
+			})
+		})
+
+		context("when the app has no Gopkg.lock", func() {
+			it.Before(func() {
+				Expect(os.Remove(filepath.Join(workingDir, "Gopkg.lock"))).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "Gopkg.toml"), nil, 0600)).To(Succeed())
+			})
+
+			it("writes a go.mod with only the module", func() {
+				migration, err := migrator.Migrate(workingDir, cacheDir, "app")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(migration.GoMod).To(Equal("module app\n"))
+				Expect(migration.Untranslatable).To(BeEmpty())
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the Gopkg.toml cannot be parsed", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "Gopkg.toml"), []byte("%%%"), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := migrator.Migrate(workingDir, cacheDir, "app")
+					Expect(err).To(MatchError(ContainSubstring("failed to parse Gopkg.toml")))
+				})
+			})
+
+			context("when the Gopkg.lock cannot be parsed", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "Gopkg.lock"), []byte("%%%"), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := migrator.Migrate(workingDir, cacheDir, "app")
+					Expect(err).To(MatchError(ContainSubstring("failed to parse Gopkg.lock")))
+				})
+			})
+		})
+	})
+}
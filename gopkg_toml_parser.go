@@ -0,0 +1,113 @@
+package dep
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/BurntSushi/toml"
+)
+
+// HerokuMetadata is the [metadata.heroku] table that apps built by the
+// Heroku and Cloud Foundry Go buildpacks carry in their Gopkg.toml.
+type HerokuMetadata struct {
+	RootPackage string   `toml:"root-package"`
+	GoVersion   string   `toml:"go-version"`
+	Install     []string `toml:"install"`
+}
+
+// ProjectConstraint is a [[constraint]] or [[override]] entry of a
+// Gopkg.toml file.
+type ProjectConstraint struct {
+	Name     string `toml:"name"`
+	Branch   string `toml:"branch"`
+	Revision string `toml:"revision"`
+	Version  string `toml:"version"`
+	Source   string `toml:"source"`
+}
+
+// GopkgConstraints are the rules that a Gopkg.toml file places on the
+// projects that dep solves for.
+type GopkgConstraints struct {
+	Constraints []ProjectConstraint `toml:"constraint"`
+	Overrides   []ProjectConstraint `toml:"override"`
+	Ignored     []string            `toml:"ignored"`
+}
+
+type gopkgManifest struct {
+	GopkgConstraints
+
+	Metadata struct {
+		Dep struct {
+			Version string `toml:"version"`
+		} `toml:"dep"`
+		Heroku HerokuMetadata `toml:"heroku"`
+	} `toml:"metadata"`
+}
+
+type GopkgTomlParser struct{}
+
+func NewGopkgTomlParser() GopkgTomlParser {
+	return GopkgTomlParser{}
+}
+
+// ParseVersion returns the dep version requested in the metadata of a
+// Gopkg.toml file:
+//
+//	[metadata.dep]
+//	  version = "~0.5"
+//
+// It returns an empty string when the file does not exist or does not specify
+// a version.
+func (p GopkgTomlParser) ParseVersion(path string) (string, error) {
+	manifest, err := p.parse(path)
+	if err != nil {
+		return "", err
+	}
+
+	return manifest.Metadata.Dep.Version, nil
+}
+
+// ParseHerokuMetadata returns the [metadata.heroku] table of a Gopkg.toml
+// file:
+//
+//	[metadata.heroku]
+//	  root-package = "github.com/some-org/some-app"
+//	  go-version = "go1.12"
+//	  install = ["./cmd/..."]
+//
+// It returns empty metadata when the file does not exist.
+func (p GopkgTomlParser) ParseHerokuMetadata(path string) (HerokuMetadata, error) {
+	manifest, err := p.parse(path)
+	if err != nil {
+		return HerokuMetadata{}, err
+	}
+
+	return manifest.Metadata.Heroku, nil
+}
+
+// ParseConstraints returns the constraints, overrides, and ignored packages
+// of a Gopkg.toml file. It returns no constraints when the file does not
+// exist.
+func (p GopkgTomlParser) ParseConstraints(path string) (GopkgConstraints, error) {
+	manifest, err := p.parse(path)
+	if err != nil {
+		return GopkgConstraints{}, err
+	}
+
+	return manifest.GopkgConstraints, nil
+}
+
+func (p GopkgTomlParser) parse(path string) (gopkgManifest, error) {
+	var manifest gopkgManifest
+	_, err := toml.DecodeFile(path, &manifest)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return gopkgManifest{}, nil
+		}
+
+		return gopkgManifest{}, fmt.Errorf("failed to parse Gopkg.toml: %w", err)
+	}
+
+	return manifest, nil
+}
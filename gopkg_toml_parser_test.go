@@ -0,0 +1,181 @@
+package dep_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/dep"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testGopkgTomlParser(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+		parser     dep.GopkgTomlParser
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = os.MkdirTemp("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+
+		parser = dep.NewGopkgTomlParser()
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	context("ParseVersion", func() {
+		it.Before(func() {
+			err := os.WriteFile(filepath.Join(workingDir, "Gopkg.toml"), []byte(`
+[[constraint]]
+  branch = "master"
+  name = "github.com/ZiCog/shiny-thing"
+
+[metadata.dep]
+  version = "~0.5"
+`), 0600)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it("parses the dep version from the Gopkg.toml metadata", func() {
+			version, err := parser.ParseVersion(filepath.Join(workingDir, "Gopkg.toml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal("~0.5"))
+		})
+
+		context("when the Gopkg.toml file does not exist", func() {
+			it("returns an empty version", func() {
+				version, err := parser.ParseVersion(filepath.Join(workingDir, "missing.toml"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(version).To(BeEmpty())
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the Gopkg.toml file cannot be parsed", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "Gopkg.toml"), []byte("%%%"), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := parser.ParseVersion(filepath.Join(workingDir, "Gopkg.toml"))
+					Expect(err).To(MatchError(ContainSubstring("failed to parse Gopkg.toml")))
+				})
+			})
+		})
+	})
+
+	context("ParseHerokuMetadata", func() {
+		it.Before(func() {
+			err := os.WriteFile(filepath.Join(workingDir, "Gopkg.toml"), []byte(`
+[[constraint]]
+  branch = "master"
+  name = "github.com/ZiCog/shiny-thing"
+
+[metadata.heroku]
+  root-package = "github.com/some-org/some-app"
+  go-version = "go1.12"
+  install = ["./cmd/some-app", "./cmd/some-tool"]
+`), 0600)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it("parses the heroku metadata from the Gopkg.toml", func() {
+			metadata, err := parser.ParseHerokuMetadata(filepath.Join(workingDir, "Gopkg.toml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metadata).To(Equal(dep.HerokuMetadata{
+				RootPackage: "github.com/some-org/some-app",
+				GoVersion:   "go1.12",
+				Install:     []string{"./cmd/some-app", "./cmd/some-tool"},
+			}))
+		})
+
+		context("when the Gopkg.toml file does not exist", func() {
+			it("returns empty metadata", func() {
+				metadata, err := parser.ParseHerokuMetadata(filepath.Join(workingDir, "missing.toml"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(metadata).To(Equal(dep.HerokuMetadata{}))
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the Gopkg.toml file cannot be parsed", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "Gopkg.toml"), []byte("%%%"), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := parser.ParseHerokuMetadata(filepath.Join(workingDir, "Gopkg.toml"))
+					Expect(err).To(MatchError(ContainSubstring("failed to parse Gopkg.toml")))
+				})
+			})
+		})
+	})
+	context("ParseConstraints", func() {
+		it.Before(func() {
+			err := os.WriteFile(filepath.Join(workingDir, "Gopkg.toml"), []byte(`
+ignored = ["github.com/some-org/some-ignored-repo"]
+
+[[constraint]]
+  name = "github.com/some-org/some-repo"
+  version = "~1.2.0"
+
+[[constraint]]
+  branch = "main"
+  name = "github.com/some-org/other-repo"
+  source = "https://git.example.com/some-org/other-repo.git"
+
+[[override]]
+  name = "github.com/some-org/some-transitive-repo"
+  revision = "some-revision"
+
+[metadata.dep]
+  version = "~0.5"
+`), 0600)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it("parses the constraints, overrides, and ignored packages", func() {
+			constraints, err := parser.ParseConstraints(filepath.Join(workingDir, "Gopkg.toml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(constraints).To(Equal(dep.GopkgConstraints{
+				Constraints: []dep.ProjectConstraint{
+					{Name: "github.com/some-org/some-repo", Version: "~1.2.0"},
+					{Name: "github.com/some-org/other-repo", Branch: "main", Source: "https://git.example.com/some-org/other-repo.git"},
+				},
+				Overrides: []dep.ProjectConstraint{
+					{Name: "github.com/some-org/some-transitive-repo", Revision: "some-revision"},
+				},
+				Ignored: []string{"github.com/some-org/some-ignored-repo"},
+			}))
+		})
+
+		context("when the Gopkg.toml file does not exist", func() {
+			it("returns no constraints", func() {
+				constraints, err := parser.ParseConstraints(filepath.Join(workingDir, "missing.toml"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(constraints).To(Equal(dep.GopkgConstraints{}))
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the Gopkg.toml file cannot be parsed", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "Gopkg.toml"), []byte("%%%"), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := parser.ParseConstraints(filepath.Join(workingDir, "Gopkg.toml"))
+					Expect(err).To(MatchError(ContainSubstring("failed to parse Gopkg.toml")))
+				})
+			})
+		})
+	})
+}
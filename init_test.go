@@ -9,7 +9,28 @@ import (
 
 func TestUnitDep(t *testing.T) {
 	suite := spec.New("dep", spec.Report(report.Terminal{}), spec.Parallel())
+	suite("ArchDependencyManager", testArchDependencyManager)
 	suite("Build", testBuild)
+	suite("BuildpackYMLParser", testBuildpackYMLParser)
+	suite("DepCheckProcess", testDepCheckProcess)
+	suite("DepEnsureProcess", testDepEnsureProcess)
+	suite("DepImportPathResolver", testDepImportPathResolver)
+	suite("DepSourceBuilder", testDepSourceBuilder)
+	suite("DependencyMappingResolver", testDependencyMappingResolver)
+	suite("DependencyMirrorResolver", testDependencyMirrorResolver)
 	suite("Detect", testDetect)
+	suite("GitCredentialsResolver", testGitCredentialsResolver)
+	suite("GitSourceRewriter", testGitSourceRewriter)
+	suite("GopkgLockParser", testGopkgLockParser)
+	suite("GopkgLockSBOMGenerator", testGopkgLockSBOMGenerator)
+	suite("GopkgModuleMigrator", testGopkgModuleMigrator)
+	suite("GopkgTomlParser", testGopkgTomlParser)
+	suite("LocalFileTransport", testLocalFileTransport)
+	suite("OSVVulnerabilityScanner", testOSVVulnerabilityScanner)
+	suite("Severity", testSeverity)
+	suite("VendorLicenseInventory", testVendorLicenseInventory)
+	suite("VendorModulesGenerator", testVendorModulesGenerator)
+	suite("VendorPruner", testVendorPruner)
+	suite("VendorVerifier", testVendorVerifier)
 	suite.Run(t)
 }
@@ -78,6 +78,11 @@ func testDefault(t *testing.T, context spec.G, it spec.S) {
 			Expect(logs).To(ContainLines(
 				MatchRegexp(fmt.Sprintf(`%s \d+\.\d+\.\d+`, buildpackInfo.Buildpack.Name)),
 				"",
+				"    Candidate version sources (in priority order):",
+				`      <unknown> -> ""`,
+				"",
+				MatchRegexp(`    Selected Dep version \(using <unknown>\): \d+\.\d+\.\d+`),
+				"",
 				"  Executing build process",
 				"    Installing Dep",
 				MatchRegexp(`      Completed in ([0-9]*(\.[0-9]*)?[a-z]+)+`),
@@ -90,6 +95,27 @@ func testDefault(t *testing.T, context spec.G, it spec.S) {
 				"    application/spdx+json",
 				"    application/vnd.syft+json",
 				"",
+				"  Configuring build environment",
+				fmt.Sprintf(`    DEPCACHEDIR -> "/layers/%s/dep-cache"`, strings.ReplaceAll(buildpackInfo.Buildpack.ID, "/", "_")),
+				fmt.Sprintf(`    DEP_HOME    -> "/layers/%s/dep"`, strings.ReplaceAll(buildpackInfo.Buildpack.ID, "/", "_")),
+				"",
+				"  Configuring launch environment",
+				fmt.Sprintf(`    DEP_HOME -> "/layers/%s/dep"`, strings.ReplaceAll(buildpackInfo.Buildpack.ID, "/", "_")),
+				"",
+				"  Vendoring dependencies",
+				"    No import path configured, using app",
+				fmt.Sprintf("    Linked app into GOPATH at /layers/%s/gopath/src/app", strings.ReplaceAll(buildpackInfo.Buildpack.ID, "/", "_")),
+				"    Running 'dep ensure'",
+				MatchRegexp(`      Completed in ([0-9]*(\.[0-9]*)?[a-z]+)+`),
+				"",
+				"  Generating SBOM for /workspace",
+				MatchRegexp(`      Completed in ([0-9]*(\.[0-9]*)?[a-z]+)+`),
+				"",
+				"  Writing SBOM in the following format(s):",
+				"    application/vnd.cyclonedx+json",
+				"    application/spdx+json",
+				"    application/vnd.syft+json",
+				"",
 			))
 
 			container, err = docker.Container.Run.WithCommand("dep --help && sleep infinity").Execute(image.ID)
@@ -115,6 +141,12 @@ func testDefault(t *testing.T, context spec.G, it spec.S) {
 			contents, err = os.ReadFile(filepath.Join(sbomDir, "sbom", "launch", strings.ReplaceAll(buildpackInfo.Buildpack.ID, "/", "_"), "dep", "sbom.cdx.json"))
 			Expect(err).NotTo(HaveOccurred())
 			Expect(string(contents)).To(ContainSubstring(`"name": "Dep"`))
+
+			// check that the vendored packages are described in the launch SBOM
+			contents, err = os.ReadFile(filepath.Join(sbomDir, "sbom", "launch", strings.ReplaceAll(buildpackInfo.Buildpack.ID, "/", "_"), "sbom.cdx.json"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(ContainSubstring(`"name": "github.com/ZiCog/shiny-thing"`))
+			Expect(string(contents)).To(ContainSubstring(`"purl": "pkg:golang/github.com/ZiCog/shiny-thing@`))
 		})
 	})
 }
@@ -0,0 +1,102 @@
+package dep
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	pfs "github.com/paketo-buildpacks/packit/v2/fs"
+)
+
+// layerManifest returns the sha256 of every regular file in dir, keyed by its
+// path relative to dir. It is recorded in the layer metadata, outside of the
+// layer itself, so that a damaged layer cannot also damage its manifest.
+func layerManifest(dir string) (map[string]interface{}, error) {
+	manifest := map[string]interface{}{}
+	calculator := pfs.NewChecksumCalculator()
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		sum, err := calculator.Sum(path)
+		if err != nil {
+			return err
+		}
+
+		manifest[filepath.ToSlash(rel)] = sum
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record layer manifest: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// layerManifestChanges describes every way in which the files in dir differ
+// from the manifest recorded when the layer was installed. Files are missing,
+// modified, or no longer executable when they are in bin.
+func layerManifestChanges(dir string, recorded interface{}) ([]string, error) {
+	manifest, ok := recorded.(map[string]interface{})
+	if !ok {
+		return []string{fmt.Sprintf("%s was not recorded", LayerManifestKey)}, nil
+	}
+
+	var paths []string
+	for path := range manifest {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	calculator := pfs.NewChecksumCalculator()
+
+	var changes []string
+	for _, path := range paths {
+		file := filepath.Join(dir, filepath.FromSlash(path))
+
+		info, err := os.Lstat(file)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				changes = append(changes, fmt.Sprintf("%s is missing", path))
+				continue
+			}
+
+			return nil, err
+		}
+
+		if !info.Mode().IsRegular() {
+			changes = append(changes, fmt.Sprintf("%s is not a regular file", path))
+			continue
+		}
+
+		if strings.HasPrefix(path, "bin/") && info.Mode().Perm()&0111 == 0 {
+			changes = append(changes, fmt.Sprintf("%s is not executable", path))
+		}
+
+		sum, err := calculator.Sum(file)
+		if err != nil {
+			return nil, err
+		}
+
+		if sum != manifest[path] {
+			changes = append(changes, fmt.Sprintf("%s does not match its recorded sha256", path))
+		}
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,47 @@
+package dep
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/paketo-buildpacks/packit/v2/postal"
+)
+
+//go:generate faux --package github.com/paketo-buildpacks/packit/v2/postal --interface Transport --output fakes/transport.go
+
+type LocalFileTransport struct {
+	transport postal.Transport
+}
+
+func NewLocalFileTransport(transport postal.Transport) LocalFileTransport {
+	return LocalFileTransport{
+		transport: transport,
+	}
+}
+
+// Drop fetches the given uri using the wrapped transport. A file:// uri is
+// resolved relative to root, as it is for dependencies packaged with an
+// offline buildpack, and falls back to the absolute path when no such file
+// exists so that dependency mappings can point at tarballs on the builder.
+func (t LocalFileTransport) Drop(root, uri string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(uri, "file://") {
+		return t.transport.Drop(root, uri)
+	}
+
+	path := strings.TrimPrefix(uri, "file://")
+	_, err := os.Stat(filepath.Join(root, path))
+	if err == nil || !errors.Is(err, os.ErrNotExist) || !filepath.IsAbs(path) {
+		return t.transport.Drop(root, uri)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	return file, nil
+}
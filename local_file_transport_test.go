@@ -0,0 +1,88 @@
+package dep_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/paketo-buildpacks/dep"
+	"github.com/paketo-buildpacks/dep/fakes"
+	"github.com/paketo-buildpacks/packit/v2/cargo"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testLocalFileTransport(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		cnbDir   string
+		localDir string
+	)
+
+	it.Before(func() {
+		var err error
+		cnbDir, err = os.MkdirTemp("", "cnb")
+		Expect(err).NotTo(HaveOccurred())
+
+		localDir, err = os.MkdirTemp("", "local")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.MkdirAll(filepath.Join(cnbDir, "dependencies"), os.ModePerm)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(cnbDir, "dependencies", "dep.tgz"), []byte("some-offline-content"), 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(localDir, "dep.tgz"), []byte("some-local-content"), 0600)).To(Succeed())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(cnbDir)).To(Succeed())
+		Expect(os.RemoveAll(localDir)).To(Succeed())
+	})
+
+	context("Drop", func() {
+		it("resolves file:// uris relative to the buildpack first", func() {
+			bundle, err := dep.NewLocalFileTransport(cargo.NewTransport()).Drop(cnbDir, "file:///dependencies/dep.tgz")
+			Expect(err).NotTo(HaveOccurred())
+			defer bundle.Close()
+
+			content, err := io.ReadAll(bundle)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("some-offline-content"))
+		})
+
+		it("falls back to the absolute path of file:// uris", func() {
+			bundle, err := dep.NewLocalFileTransport(cargo.NewTransport()).Drop(cnbDir, "file://"+filepath.Join(localDir, "dep.tgz"))
+			Expect(err).NotTo(HaveOccurred())
+			defer bundle.Close()
+
+			content, err := io.ReadAll(bundle)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("some-local-content"))
+		})
+
+		it("delegates other uris to the wrapped transport", func() {
+			transport := &fakes.Transport{}
+			transport.DropCall.Returns.ReadCloser = io.NopCloser(strings.NewReader("some-remote-content"))
+
+			bundle, err := dep.NewLocalFileTransport(transport).Drop(cnbDir, "https://example.com/dep.tgz")
+			Expect(err).NotTo(HaveOccurred())
+			defer bundle.Close()
+
+			Expect(transport.DropCall.Receives.Root).To(Equal(cnbDir))
+			Expect(transport.DropCall.Receives.Uri).To(Equal("https://example.com/dep.tgz"))
+		})
+
+		context("failure cases", func() {
+			context("when the file does not exist", func() {
+				it("returns an error", func() {
+					_, err := dep.NewLocalFileTransport(cargo.NewTransport()).Drop(cnbDir, "file://"+filepath.Join(localDir, "missing.tgz"))
+					Expect(errors.Is(err, os.ErrNotExist)).To(BeTrue())
+					Expect(err).To(MatchError(ContainSubstring("failed to open file")))
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,379 @@
+package dep
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// VulnerabilityFinding is a known vulnerability that affects a locked
+// project.
+type VulnerabilityFinding struct {
+	Project  string
+	Version  string
+	ID       string
+	Severity Severity
+	Fixed    string
+}
+
+// VulnerabilityScan is the result of matching the locked projects of an app
+// against a vulnerability database.
+type VulnerabilityScan struct {
+	// Database describes where the database was read from. It is empty when
+	// no database is configured.
+	Database string
+	Findings []VulnerabilityFinding
+
+	// Unverifiable lists the projects that have known vulnerabilities which
+	// could not be checked against the locked revision.
+	Unverifiable []string
+}
+
+type osvEntry struct {
+	ID       string `json:"id"`
+	Affected []struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		Ranges []struct {
+			Type   string     `json:"type"`
+			Events []osvEvent `json:"events"`
+		} `json:"ranges"`
+		Versions         []string `json:"versions"`
+		DatabaseSpecific struct {
+			Severity string `json:"severity"`
+		} `json:"database_specific"`
+	} `json:"affected"`
+	Severity []struct {
+		Type  string `json:"type"`
+		Score string `json:"score"`
+	} `json:"severity"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+}
+
+type osvEvent struct {
+	Introduced   string `json:"introduced"`
+	Fixed        string `json:"fixed"`
+	LastAffected string `json:"last_affected"`
+}
+
+type OSVVulnerabilityScanner struct {
+	bindingResolver BindingResolver
+	lockParser      GopkgLockParser
+}
+
+func NewOSVVulnerabilityScanner(bindingResolver BindingResolver) OSVVulnerabilityScanner {
+	return OSVVulnerabilityScanner{
+		bindingResolver: bindingResolver,
+		lockParser:      NewGopkgLockParser(),
+	}
+}
+
+// Scan matches the projects in the Gopkg.lock in workingDir against an OSV
+// vulnerability database without any network access. The database is a
+// directory of OSV JSON files, or zip archives of them such as the exports of
+// osv.dev, and is read from the path in BP_DEP_OSV_DATABASE or otherwise from
+// every "osv-database" service binding.
+//
+// Projects are matched by import path, and a module with a /vN suffix only
+// matches a project that is locked to major version N. A project that is
+// locked to a version is affected when the version is listed by the
+// vulnerability or falls in one of its SEMVER or ECOSYSTEM ranges. A project
+// that is only locked to a revision is reported as unverifiable, as the order
+// of the commits in a GIT range cannot be resolved without its repository.
+func (s OSVVulnerabilityScanner) Scan(workingDir, platformDir string) (VulnerabilityScan, error) {
+	var scan VulnerabilityScan
+	var paths []string
+	if path, ok := os.LookupEnv("BP_DEP_OSV_DATABASE"); ok {
+		scan.Database = path
+		paths = append(paths, path)
+	} else {
+		bindings, err := s.bindingResolver.Resolve("osv-database", "", platformDir)
+		if err != nil {
+			return VulnerabilityScan{}, fmt.Errorf("failed to resolve 'osv-database' binding: %w", err)
+		}
+		sortBindings(bindings)
+
+		var names []string
+		for _, binding := range bindings {
+			names = append(names, binding.Name)
+			paths = append(paths, binding.Path)
+		}
+
+		if len(names) > 0 {
+			scan.Database = fmt.Sprintf("binding %s", strings.Join(names, ", "))
+		}
+	}
+
+	if scan.Database == "" {
+		return VulnerabilityScan{}, nil
+	}
+
+	var entries []osvEntry
+	for _, path := range paths {
+		loaded, err := loadOSVDatabase(path)
+		if err != nil {
+			return VulnerabilityScan{}, fmt.Errorf("failed to load OSV database: %w", err)
+		}
+
+		entries = append(entries, loaded...)
+	}
+
+	projects, err := s.lockParser.Parse(filepath.Join(workingDir, GopkgLock))
+	if err != nil {
+		return VulnerabilityScan{}, err
+	}
+
+	for _, project := range projects {
+		var unverifiable bool
+		for _, entry := range entries {
+			affected, fixed, known := entry.affects(project)
+			if !known {
+				unverifiable = true
+				continue
+			}
+
+			if !affected {
+				continue
+			}
+
+			version := project.Version
+			if version == "" {
+				version = project.Revision
+			}
+
+			scan.Findings = append(scan.Findings, VulnerabilityFinding{
+				Project:  project.Name,
+				Version:  version,
+				ID:       entry.ID,
+				Severity: entry.severity(project),
+				Fixed:    fixed,
+			})
+		}
+
+		if unverifiable {
+			scan.Unverifiable = append(scan.Unverifiable, project.Name)
+		}
+	}
+
+	sort.SliceStable(scan.Findings, func(i, j int) bool {
+		return scan.Findings[i].Severity > scan.Findings[j].Severity
+	})
+
+	return scan, nil
+}
+
+// affects reports whether the entry affects the locked project and the version
+// that fixes it. It reports that the result is not known when the entry
+// applies to the project but cannot be compared to the locked revision.
+func (e osvEntry) affects(project LockedProject) (bool, string, bool) {
+	known := true
+	for _, affected := range e.Affected {
+		if affected.Package.Ecosystem != "" && affected.Package.Ecosystem != "Go" {
+			continue
+		}
+
+		if !belongsToProject(affected.Package.Name, project) {
+			continue
+		}
+
+		version, err := semver.NewVersion(project.Version)
+		if project.Version == "" || err != nil {
+			known = false
+			continue
+		}
+
+		for _, listed := range affected.Versions {
+			if v, err := semver.NewVersion(listed); err == nil && v.Equal(version) {
+				return true, "", true
+			}
+		}
+
+		for _, r := range affected.Ranges {
+			if r.Type != "SEMVER" && r.Type != "ECOSYSTEM" {
+				continue
+			}
+
+			var vulnerable bool
+			var fixed string
+			for _, event := range r.Events {
+				switch {
+				case event.Introduced != "":
+					introduced, err := semver.NewVersion(event.Introduced)
+					if event.Introduced == "0" || (err == nil && !version.LessThan(introduced)) {
+						vulnerable = true
+					}
+				case event.Fixed != "":
+					f, err := semver.NewVersion(event.Fixed)
+					if err != nil {
+						continue
+					}
+
+					if !version.LessThan(f) {
+						vulnerable = false
+					} else if vulnerable && fixed == "" {
+						fixed = event.Fixed
+					}
+				case event.LastAffected != "":
+					last, err := semver.NewVersion(event.LastAffected)
+					if err == nil && version.GreaterThan(last) {
+						vulnerable = false
+					}
+				}
+			}
+
+			if vulnerable {
+				return true, fixed, true
+			}
+		}
+	}
+
+	return false, "", known
+}
+
+// severity returns the severity of the entry for the given project, as
+// recorded by the database that the entry comes from or otherwise as derived
+// from its CVSS v3 score.
+func (e osvEntry) severity(project LockedProject) Severity {
+	named := []string{e.DatabaseSpecific.Severity}
+	for _, affected := range e.Affected {
+		if belongsToProject(affected.Package.Name, project) {
+			named = append([]string{affected.DatabaseSpecific.Severity}, named...)
+		}
+	}
+
+	for _, name := range named {
+		if severity, err := ParseSeverity(name); err == nil {
+			return severity
+		}
+	}
+
+	for _, severity := range e.Severity {
+		if severity.Type == "CVSS_V3" {
+			if s := cvssV3Severity(severity.Score); s != SeverityUnknown {
+				return s
+			}
+		}
+	}
+
+	return SeverityUnknown
+}
+
+// belongsToProject reports whether a Go module or package belongs to the
+// locked project. The import path of a dep project does not change with its
+// major version, so a module with a /vN suffix belongs to it only when it is
+// locked to major version N, and to any project that is not locked to a
+// semantic version.
+func belongsToProject(name string, project LockedProject) bool {
+	if name == project.Name {
+		return true
+	}
+
+	if !strings.HasPrefix(name, project.Name+"/") {
+		return false
+	}
+
+	first, _, _ := strings.Cut(strings.TrimPrefix(name, project.Name+"/"), "/")
+	match := majorVersionSuffix.FindStringSubmatch("/" + first)
+	if match == nil {
+		return true
+	}
+
+	version, err := semver.NewVersion(project.Version)
+	if err != nil {
+		return true
+	}
+
+	return fmt.Sprintf("%d", version.Major()) == match[1]
+}
+
+// loadOSVDatabase reads every OSV entry in the JSON files and zip archives
+// found at path, which may be a file or a directory.
+func loadOSVDatabase(path string) ([]osvEntry, error) {
+	var entries []osvEntry
+	err := filepath.WalkDir(path, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			return nil
+		case strings.HasSuffix(file, ".json"):
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+
+			entry, err := parseOSVEntry(file, content)
+			if err != nil {
+				return err
+			}
+
+			entries = append(entries, entry)
+		case strings.HasSuffix(file, ".zip"):
+			archive, err := zip.OpenReader(file)
+			if err != nil {
+				return fmt.Errorf("failed to open %s: %w", file, err)
+			}
+			defer archive.Close()
+
+			for _, f := range archive.File {
+				if !strings.HasSuffix(f.Name, ".json") {
+					continue
+				}
+
+				reader, err := f.Open()
+				if err != nil {
+					return err
+				}
+
+				content, err := io.ReadAll(reader)
+				reader.Close()
+				if err != nil {
+					return err
+				}
+
+				entry, err := parseOSVEntry(fmt.Sprintf("%s:%s", file, f.Name), content)
+				if err != nil {
+					return err
+				}
+
+				entries = append(entries, entry)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%s does not exist", path)
+		}
+
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func parseOSVEntry(name string, content []byte) (osvEntry, error) {
+	var entry osvEntry
+	err := json.Unmarshal(content, &entry)
+	if err != nil {
+		return osvEntry{}, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,319 @@
+package dep_test
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/dep"
+	"github.com/paketo-buildpacks/dep/fakes"
+	"github.com/paketo-buildpacks/packit/v2/servicebindings"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testOSVVulnerabilityScanner(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir      string
+		databaseDir     string
+		bindingResolver *fakes.BindingResolver
+		scanner         dep.OSVVulnerabilityScanner
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = os.MkdirTemp("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+
+		databaseDir, err = os.MkdirTemp("", "osv-database")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = os.WriteFile(filepath.Join(workingDir, "Gopkg.lock"), []byte(`
+[[projects]]
+  digest = "1:some-digest"
+  name = "github.com/some-org/some-repo"
+  packages = ["."]
+  revision = "1111111111111111111111111111111111111111"
+  version = "v1.2.0"
+
+[[projects]]
+  digest = "1:other-digest"
+  name = "github.com/some-org/other-repo"
+  packages = ["subpkg"]
+  revision = "2222222222222222222222222222222222222222"
+  version = "v0.1.0"
+
+[[projects]]
+  branch = "master"
+  digest = "1:branch-digest"
+  name = "github.com/some-org/branch-repo"
+  packages = ["."]
+  revision = "3333333333333333333333333333333333333333"
+
+[[projects]]
+  digest = "1:unversioned-digest"
+  name = "github.com/some-org/unversioned-repo"
+  packages = ["."]
+  revision = "4444444444444444444444444444444444444444"
+
+[[projects]]
+  digest = "1:major-digest"
+  name = "github.com/some-org/major-repo"
+  packages = ["."]
+  revision = "6666666666666666666666666666666666666666"
+  version = "v2.0.1"
+
+[solve-meta]
+  analyzer-name = "dep"
+  analyzer-version = 1
+`), 0600)
+		Expect(err).NotTo(HaveOccurred())
+
+		bindingResolver = &fakes.BindingResolver{}
+		scanner = dep.NewOSVVulnerabilityScanner(bindingResolver)
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+		Expect(os.RemoveAll(databaseDir)).To(Succeed())
+	})
+
+	entries := map[string]string{
+		// affects some-repo before 1.2.1, with a GitHub advisory severity
+		"GO-2022-0001.json": `{
+			"id": "GO-2022-0001",
+			"affected": [{
+				"package": {"ecosystem": "Go", "name": "github.com/some-org/some-repo"},
+				"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "1.2.1"}]}]
+			}],
+			"database_specific": {"severity": "HIGH"}
+		}`,
+		// affects some-repo only from 1.3.0 onwards
+		"GO-2022-0002.json": `{
+			"id": "GO-2022-0002",
+			"affected": [{
+				"package": {"ecosystem": "Go", "name": "github.com/some-org/some-repo"},
+				"ranges": [{"type": "SEMVER", "events": [{"introduced": "1.3.0"}, {"fixed": "1.3.2"}]}]
+			}]
+		}`,
+		// lists the affected version of a package of other-repo, scored by CVSS
+		"nested/GO-2022-0003.json": `{
+			"id": "GO-2022-0003",
+			"affected": [{
+				"package": {"ecosystem": "Go", "name": "github.com/some-org/other-repo/subpkg"},
+				"versions": ["v0.1.0"]
+			}],
+			"severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}]
+		}`,
+		// names the locked revision of branch-repo, but the commits of a GIT
+		// range cannot be ordered without the repository
+		"GO-2022-0004.json": `{
+			"id": "GO-2022-0004",
+			"affected": [{
+				"package": {"name": "github.com/some-org/branch-repo"},
+				"ranges": [{"type": "GIT", "repo": "https://github.com/some-org/branch-repo", "events": [{"introduced": "3333333333333333333333333333333333333333"}, {"fixed": "5555555555555555555555555555555555555555"}]}]
+			}]
+		}`,
+		// cannot be compared to the revision of unversioned-repo
+		"GO-2022-0005.json": `{
+			"id": "GO-2022-0005",
+			"affected": [{
+				"package": {"ecosystem": "Go", "name": "github.com/some-org/unversioned-repo"},
+				"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "2.0.0"}]}]
+			}]
+		}`,
+		// belongs to another ecosystem
+		"GHSA-some-npm.json": `{
+			"id": "GHSA-some-npm",
+			"affected": [{
+				"package": {"ecosystem": "npm", "name": "github.com/some-org/other-repo"},
+				"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}]}]
+			}]
+		}`,
+		// was last present in 0.0.9
+		"GO-2022-0006.json": `{
+			"id": "GO-2022-0006",
+			"affected": [{
+				"package": {"ecosystem": "Go", "name": "github.com/some-org/other-repo"},
+				"ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"last_affected": "0.0.9"}]}]
+			}]
+		}`,
+		// affects the v2 module of some-repo, which is locked at v1
+		"GO-2022-0007.json": `{
+			"id": "GO-2022-0007",
+			"affected": [{
+				"package": {"ecosystem": "Go", "name": "github.com/some-org/some-repo/v2"},
+				"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}]}]
+			}]
+		}`,
+		// affects the v2 module of major-repo, which is locked at v2
+		"GO-2022-0008.json": `{
+			"id": "GO-2022-0008",
+			"affected": [{
+				"package": {"ecosystem": "Go", "name": "github.com/some-org/major-repo/v2"},
+				"ranges": [{"type": "SEMVER", "events": [{"introduced": "2.0.0"}, {"fixed": "2.0.2"}]}]
+			}]
+		}`,
+	}
+
+	findings := []dep.VulnerabilityFinding{
+		{
+			Project:  "github.com/some-org/other-repo",
+			Version:  "v0.1.0",
+			ID:       "GO-2022-0003",
+			Severity: dep.SeverityCritical,
+		},
+		{
+			Project:  "github.com/some-org/some-repo",
+			Version:  "v1.2.0",
+			ID:       "GO-2022-0001",
+			Severity: dep.SeverityHigh,
+			Fixed:    "1.2.1",
+		},
+		{
+			Project:  "github.com/some-org/major-repo",
+			Version:  "v2.0.1",
+			ID:       "GO-2022-0008",
+			Severity: dep.SeverityUnknown,
+			Fixed:    "2.0.2",
+		},
+	}
+
+	context("Scan", func() {
+		context("when no database is configured", func() {
+			it("returns an empty scan", func() {
+				scan, err := scanner.Scan(workingDir, "some-platform")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(scan).To(Equal(dep.VulnerabilityScan{}))
+
+				Expect(bindingResolver.ResolveCall.Receives.Typ).To(Equal("osv-database"))
+				Expect(bindingResolver.ResolveCall.Receives.PlatformDir).To(Equal("some-platform"))
+			})
+		})
+
+		context("when a database directory is bound", func() {
+			it.Before(func() {
+				for name, content := range entries {
+					path := filepath.Join(databaseDir, name)
+					Expect(os.MkdirAll(filepath.Dir(path), os.ModePerm)).To(Succeed())
+					Expect(os.WriteFile(path, []byte(content), 0600)).To(Succeed())
+				}
+
+				bindingResolver.ResolveCall.Returns.BindingSlice = []servicebindings.Binding{
+					{Name: "some-osv-database", Type: "osv-database", Path: databaseDir},
+				}
+			})
+
+			it("returns the vulnerabilities of the locked projects", func() {
+				scan, err := scanner.Scan(workingDir, "some-platform")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(scan).To(Equal(dep.VulnerabilityScan{
+					Database:     "binding some-osv-database",
+					Findings:     findings,
+					Unverifiable: []string{"github.com/some-org/branch-repo", "github.com/some-org/unversioned-repo"},
+				}))
+			})
+		})
+
+		context("when BP_DEP_OSV_DATABASE points at a zip archive", func() {
+			var archivePath string
+
+			it.Before(func() {
+				archivePath = filepath.Join(databaseDir, "all.zip")
+				file, err := os.Create(archivePath)
+				Expect(err).NotTo(HaveOccurred())
+
+				writer := zip.NewWriter(file)
+				for name, content := range entries {
+					w, err := writer.Create(name)
+					Expect(err).NotTo(HaveOccurred())
+
+					_, err = w.Write([]byte(content))
+					Expect(err).NotTo(HaveOccurred())
+				}
+				Expect(writer.Close()).To(Succeed())
+				Expect(file.Close()).To(Succeed())
+
+				Expect(os.Setenv("BP_DEP_OSV_DATABASE", archivePath)).To(Succeed())
+			})
+
+			it.After(func() {
+				Expect(os.Unsetenv("BP_DEP_OSV_DATABASE")).To(Succeed())
+			})
+
+			it("reads the entries of the archive instead of any binding", func() {
+				scan, err := scanner.Scan(workingDir, "some-platform")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(scan).To(Equal(dep.VulnerabilityScan{
+					Database:     archivePath,
+					Findings:     findings,
+					Unverifiable: []string{"github.com/some-org/branch-repo", "github.com/some-org/unversioned-repo"},
+				}))
+
+				Expect(bindingResolver.ResolveCall.CallCount).To(Equal(0))
+			})
+		}, spec.Sequential())
+
+		context("failure cases", func() {
+			context("when the binding cannot be resolved", func() {
+				it.Before(func() {
+					bindingResolver.ResolveCall.Returns.Error = errors.New("some-error")
+				})
+
+				it("returns an error", func() {
+					_, err := scanner.Scan(workingDir, "some-platform")
+					Expect(err).To(MatchError("failed to resolve 'osv-database' binding: some-error"))
+				})
+			})
+
+			context("when the database does not exist", func() {
+				it.Before(func() {
+					bindingResolver.ResolveCall.Returns.BindingSlice = []servicebindings.Binding{
+						{Name: "some-osv-database", Path: filepath.Join(databaseDir, "missing")},
+					}
+				})
+
+				it("returns an error", func() {
+					_, err := scanner.Scan(workingDir, "some-platform")
+					Expect(err).To(MatchError(fmt.Sprintf("failed to load OSV database: %s does not exist", filepath.Join(databaseDir, "missing"))))
+				})
+			})
+
+			context("when an entry cannot be parsed", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(databaseDir, "GO-2022-0001.json"), []byte("%%%"), 0600)).To(Succeed())
+
+					bindingResolver.ResolveCall.Returns.BindingSlice = []servicebindings.Binding{
+						{Name: "some-osv-database", Path: databaseDir},
+					}
+				})
+
+				it("returns an error", func() {
+					_, err := scanner.Scan(workingDir, "some-platform")
+					Expect(err).To(MatchError(ContainSubstring(fmt.Sprintf("failed to load OSV database: failed to parse %s", filepath.Join(databaseDir, "GO-2022-0001.json")))))
+				})
+			})
+
+			context("when the Gopkg.lock cannot be parsed", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "Gopkg.lock"), []byte("%%%"), 0600)).To(Succeed())
+
+					bindingResolver.ResolveCall.Returns.BindingSlice = []servicebindings.Binding{
+						{Name: "some-osv-database", Path: databaseDir},
+					}
+				})
+
+				it("returns an error", func() {
+					_, err := scanner.Scan(workingDir, "some-platform")
+					Expect(err).To(MatchError(ContainSubstring("failed to parse Gopkg.lock")))
+				})
+			})
+		})
+	})
+}
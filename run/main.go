@@ -8,9 +8,11 @@ import (
 	"github.com/paketo-buildpacks/packit/v2/cargo"
 	"github.com/paketo-buildpacks/packit/v2/chronos"
 	"github.com/paketo-buildpacks/packit/v2/draft"
+	"github.com/paketo-buildpacks/packit/v2/pexec"
 	"github.com/paketo-buildpacks/packit/v2/postal"
 	"github.com/paketo-buildpacks/packit/v2/sbom"
 	"github.com/paketo-buildpacks/packit/v2/scribe"
+	"github.com/paketo-buildpacks/packit/v2/servicebindings"
 )
 
 type Generator struct{}
@@ -21,13 +23,30 @@ func (f Generator) GenerateFromDependency(dependency postal.Dependency, path str
 
 func main() {
 	logEmitter := scribe.NewEmitter(os.Stdout).WithLevel(os.Getenv("BP_LOG_LEVEL"))
+	bindingResolver := servicebindings.NewResolver()
+	transport := dep.NewLocalFileTransport(cargo.NewTransport())
 
 	packit.Run(
-		dep.Detect(),
+		dep.Detect(dep.NewBuildpackYMLParser(), dep.NewGopkgTomlParser()),
 		dep.Build(
 			draft.NewPlanner(),
-			postal.NewService(cargo.NewTransport()),
+			dep.NewArchDependencyManager(postal.NewService(transport)),
+			dep.NewDependencyMappingResolver(bindingResolver),
+			dep.NewDependencyMirrorResolver(bindingResolver),
+			dep.NewDepSourceBuilder(transport, pexec.NewExecutable("go"), logEmitter),
+			dep.NewDepImportPathResolver(dep.NewGopkgTomlParser()),
+			dep.NewGitCredentialsResolver(bindingResolver),
+			dep.NewGitSourceRewriter(bindingResolver),
+			dep.NewDepCheckProcess(pexec.NewExecutable("dep"), logEmitter),
+			dep.NewGopkgLockVendorVerifier(),
+			dep.NewDepEnsureProcess(pexec.NewExecutable("dep"), logEmitter),
+			dep.NewGopkgLockVendorPruner(),
+			dep.NewGopkgModuleMigrator(pexec.NewExecutable("git")),
+			dep.NewGopkgLockVendorModulesGenerator(),
+			dep.NewOSVVulnerabilityScanner(bindingResolver),
+			dep.NewVendorLicenseInventory(),
 			Generator{},
+			dep.NewGopkgLockSBOMGenerator(),
 			chronos.DefaultClock,
 			logEmitter,
 		),
@@ -0,0 +1,137 @@
+package dep
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Severity ranks how severe a vulnerability is.
+type Severity int
+
+const (
+	SeverityUnknown Severity = iota
+	SeverityLow
+	SeverityModerate
+	SeverityHigh
+	SeverityCritical
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityLow:
+		return "LOW"
+	case SeverityModerate:
+		return "MODERATE"
+	case SeverityHigh:
+		return "HIGH"
+	case SeverityCritical:
+		return "CRITICAL"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ParseSeverity parses a severity name such as "high". Both "moderate" and
+// "medium" are accepted for the moderate severity.
+func ParseSeverity(value string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "low":
+		return SeverityLow, nil
+	case "moderate", "medium":
+		return SeverityModerate, nil
+	case "high":
+		return SeverityHigh, nil
+	case "critical":
+		return SeverityCritical, nil
+	default:
+		return SeverityUnknown, fmt.Errorf("unknown severity %q, expected one of low, moderate, high, or critical", value)
+	}
+}
+
+// cvssV3Severity returns the severity of the base score of a CVSS v3 vector
+// such as "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H".
+func cvssV3Severity(vector string) Severity {
+	weights := map[string]map[string]float64{
+		"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+		"AC": {"L": 0.77, "H": 0.44},
+		"UI": {"N": 0.85, "R": 0.62},
+		"C":  {"H": 0.56, "L": 0.22, "N": 0},
+		"I":  {"H": 0.56, "L": 0.22, "N": 0},
+		"A":  {"H": 0.56, "L": 0.22, "N": 0},
+	}
+
+	metrics := map[string]string{}
+	for _, part := range strings.Split(vector, "/") {
+		if key, value, ok := strings.Cut(part, ":"); ok {
+			metrics[key] = value
+		}
+	}
+
+	if !strings.HasPrefix(metrics["CVSS"], "3") {
+		return SeverityUnknown
+	}
+
+	values := map[string]float64{}
+	for metric, options := range weights {
+		value, ok := options[metrics[metric]]
+		if !ok {
+			return SeverityUnknown
+		}
+		values[metric] = value
+	}
+
+	changed := metrics["S"] == "C"
+	if !changed && metrics["S"] != "U" {
+		return SeverityUnknown
+	}
+
+	privileges := map[string]float64{"N": 0.85, "L": 0.62, "H": 0.27}
+	if changed {
+		privileges = map[string]float64{"N": 0.85, "L": 0.68, "H": 0.5}
+	}
+
+	pr, ok := privileges[metrics["PR"]]
+	if !ok {
+		return SeverityUnknown
+	}
+
+	iss := 1 - (1-values["C"])*(1-values["I"])*(1-values["A"])
+	impact := 6.42 * iss
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+
+	if impact <= 0 {
+		return SeverityUnknown
+	}
+
+	exploitability := 8.22 * values["AV"] * values["AC"] * pr * values["UI"]
+
+	score := impact + exploitability
+	if changed {
+		score *= 1.08
+	}
+	score = roundUp(math.Min(score, 10))
+
+	switch {
+	case score >= 9:
+		return SeverityCritical
+	case score >= 7:
+		return SeverityHigh
+	case score >= 4:
+		return SeverityModerate
+	default:
+		return SeverityLow
+	}
+}
+
+// roundUp rounds up to one decimal as defined by CVSS v3.1.
+func roundUp(value float64) float64 {
+	integer := int(math.Round(value * 100000))
+	if integer%10000 == 0 {
+		return float64(integer) / 100000
+	}
+
+	return float64(integer/10000+1) / 10
+}
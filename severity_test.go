@@ -0,0 +1,47 @@
+package dep_test
+
+import (
+	"testing"
+
+	"github.com/paketo-buildpacks/dep"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testSeverity(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("ParseSeverity", func() {
+		it("parses the severity names regardless of case", func() {
+			for value, severity := range map[string]dep.Severity{
+				"low":      dep.SeverityLow,
+				"Moderate": dep.SeverityModerate,
+				"medium":   dep.SeverityModerate,
+				"HIGH":     dep.SeverityHigh,
+				"critical": dep.SeverityCritical,
+			} {
+				parsed, err := dep.ParseSeverity(value)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parsed).To(Equal(severity))
+			}
+		})
+
+		it("orders the severities", func() {
+			Expect(dep.SeverityUnknown).To(BeNumerically("<", dep.SeverityLow))
+			Expect(dep.SeverityLow).To(BeNumerically("<", dep.SeverityModerate))
+			Expect(dep.SeverityModerate).To(BeNumerically("<", dep.SeverityHigh))
+			Expect(dep.SeverityHigh).To(BeNumerically("<", dep.SeverityCritical))
+			Expect(dep.SeverityCritical.String()).To(Equal("CRITICAL"))
+		})
+
+		context("failure cases", func() {
+			context("when the severity is unknown", func() {
+				it("returns an error", func() {
+					_, err := dep.ParseSeverity("severe")
+					Expect(err).To(MatchError(`unknown severity "severe", expected one of low, moderate, high, or critical`))
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,222 @@
+package dep
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var spdxIdentifier = regexp.MustCompile(`SPDX-License-Identifier:\s*([A-Za-z0-9.+-]+)`)
+
+// licenseRules classify a license text by phrases that only appear in that
+// license. The GNU licenses refer to each other throughout their texts, so
+// they are matched by their full title, which includes the version. The rules
+// are tried in order, so a license that names another by its full title, like
+// the MPL naming the GPL as a secondary license or the LGPL naming the GPL that
+// it applies, comes before it.
+var licenseRules = []struct {
+	ID      string
+	Phrases []string
+}{
+	{"MPL-2.0", []string{"mozilla public license version 2 0"}},
+	{"EPL-2.0", []string{"eclipse public license v 2 0"}},
+	{"EPL-1.0", []string{"eclipse public license v 1 0"}},
+	{"AGPL-3.0", []string{"gnu affero general public license version 3"}},
+	{"LGPL-3.0", []string{"gnu lesser general public license version 3"}},
+	{"LGPL-2.1", []string{"gnu lesser general public license version 2 1"}},
+	{"LGPL-2.0", []string{"gnu library general public license version 2"}},
+	{"GPL-3.0", []string{"gnu general public license version 3"}},
+	{"GPL-2.0", []string{"gnu general public license version 2"}},
+	{"Apache-2.0", []string{"apache license", "version 2 0"}},
+	{"BSL-1.0", []string{"boost software license version 1 0"}},
+	{"Unlicense", []string{"this is free and unencumbered software released into the public domain"}},
+	{"CC0-1.0", []string{"cc0 1 0 universal"}},
+	{"MIT", []string{"permission is hereby granted free of charge to any person obtaining a copy"}},
+	{"ISC", []string{"distribute this software for any purpose with or without fee is hereby granted"}},
+	{"BSD-3-Clause", []string{"redistribution and use in source and binary forms", "may be used to endorse or promote products derived from this software"}},
+	{"BSD-2-Clause", []string{"redistribution and use in source and binary forms"}},
+	{"Zlib", []string{"altered source versions must be plainly marked as such"}},
+}
+
+// ProjectLicenses are the licenses found in the vendored copy of a project.
+type ProjectLicenses struct {
+	Project string
+
+	// Licenses are the SPDX identifiers of the license files of the project.
+	Licenses []string
+
+	// Unclassified are the license files, relative to vendor, whose license
+	// could not be identified.
+	Unclassified []string
+}
+
+type VendorLicenseInventory struct {
+	parser GopkgLockParser
+}
+
+func NewVendorLicenseInventory() VendorLicenseInventory {
+	return VendorLicenseInventory{
+		parser: NewGopkgLockParser(),
+	}
+}
+
+// Collect returns the licenses of every project in the vendor directory of
+// workingDir, sorted by project. The projects are those locked in the
+// Gopkg.lock. License files outside of them, or in a vendor directory without
+// a Gopkg.lock, belong to the directory that contains them.
+func (i VendorLicenseInventory) Collect(workingDir string) ([]ProjectLicenses, error) {
+	vendorDir := filepath.Join(workingDir, "vendor")
+	if _, err := os.Stat(vendorDir); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	var inventory []ProjectLicenses
+	projects := map[string]int{}
+
+	locked, err := i.parser.Parse(filepath.Join(workingDir, GopkgLock))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, project := range locked {
+		projects[project.Name] = len(inventory)
+		inventory = append(inventory, ProjectLicenses{Project: project.Name})
+	}
+
+	err = filepath.WalkDir(vendorDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || !isLicenseFile(d.Name()) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(vendorDir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		project := owningProject(projects, filepath.ToSlash(filepath.Dir(rel)))
+		index, ok := projects[project]
+		if !ok {
+			index = len(inventory)
+			projects[project] = index
+			inventory = append(inventory, ProjectLicenses{Project: project})
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		id := classifyLicense(content)
+		if id == "" {
+			inventory[index].Unclassified = append(inventory[index].Unclassified, rel)
+			return nil
+		}
+
+		if !contains(inventory[index].Licenses, id) {
+			inventory[index].Licenses = append(inventory[index].Licenses, id)
+			sort.Strings(inventory[index].Licenses)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect vendored licenses: %w", err)
+	}
+
+	sort.Slice(inventory, func(a, b int) bool {
+		return inventory[a].Project < inventory[b].Project
+	})
+
+	return inventory, nil
+}
+
+// owningProject returns the known project that contains dir, or dir itself
+// when there is none.
+func owningProject(projects map[string]int, dir string) string {
+	for parent := dir; parent != "."; parent = filepath.ToSlash(filepath.Dir(parent)) {
+		if _, ok := projects[parent]; ok {
+			return parent
+		}
+	}
+
+	return dir
+}
+
+func isLicenseFile(name string) bool {
+	upper := strings.ToUpper(name)
+	if strings.HasSuffix(upper, ".GO") {
+		return false
+	}
+
+	for _, prefix := range []string{"LICENSE", "LICENCE", "COPYING", "UNLICENSE"} {
+		if strings.HasPrefix(upper, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// classifyLicense returns the SPDX identifier of a license text, or an empty
+// string when it is not recognized. An SPDX-License-Identifier tag takes
+// precedence over the text itself.
+func classifyLicense(content []byte) string {
+	if match := spdxIdentifier.FindSubmatch(content); match != nil {
+		return string(match[1])
+	}
+
+	text := normalizeLicense(string(content))
+	for _, rule := range licenseRules {
+		matched := true
+		for _, phrase := range rule.Phrases {
+			if !strings.Contains(text, phrase) {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return rule.ID
+		}
+	}
+
+	return ""
+}
+
+// normalizeLicense lowercases a license text and reduces everything but
+// letters and digits to single spaces, so that line wrapping and punctuation do
+// not affect classification.
+func normalizeLicense(text string) string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+
+	return strings.Join(fields, " ")
+}
+
+// licenseDenied returns the denied licenses among the given ones. A deny list
+// entry matches a license with the same identifier or one that refines it,
+// so "GPL" denies "GPL-3.0" but not "LGPL-3.0".
+func licenseDenied(licenses, denyList []string) []string {
+	var denied []string
+	for _, license := range licenses {
+		for _, entry := range denyList {
+			if strings.EqualFold(license, entry) || strings.HasPrefix(strings.ToLower(license), strings.ToLower(entry)+"-") {
+				denied = append(denied, license)
+				break
+			}
+		}
+	}
+
+	return denied
+}
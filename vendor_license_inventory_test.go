@@ -0,0 +1,182 @@
+package dep_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/paketo-buildpacks/dep"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testVendorLicenseInventory(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+		inventory  dep.VendorLicenseInventory
+	)
+
+	writeFile := func(path, content string) {
+		path = filepath.Join(workingDir, filepath.FromSlash(path))
+		Expect(os.MkdirAll(filepath.Dir(path), os.ModePerm)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(content), 0600)).To(Succeed())
+	}
+
+	it.Before(func() {
+		var err error
+		workingDir, err = os.MkdirTemp("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+
+		inventory = dep.NewVendorLicenseInventory()
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	context("Collect", func() {
+		context("when the app has a vendor directory", func() {
+			it.Before(func() {
+				writeFile("Gopkg.lock", `
+[[projects]]
+  name = "github.com/some-org/agpl-repo"
+  packages = ["."]
+  revision = "some-revision"
+
+[[projects]]
+  name = "github.com/some-org/bsd-repo"
+  packages = ["."]
+  revision = "some-revision"
+
+[[projects]]
+  name = "github.com/some-org/mit-repo"
+  packages = [".", "sub"]
+  revision = "some-revision"
+
+[[projects]]
+  name = "github.com/some-org/no-license-repo"
+  packages = ["."]
+  revision = "some-revision"
+
+[[projects]]
+  name = "github.com/some-org/spdx-repo"
+  packages = ["."]
+  revision = "some-revision"
+`)
+
+				writeFile("vendor/github.com/some-org/agpl-repo/COPYING", `
+                    GNU AFFERO GENERAL PUBLIC LICENSE
+                       Version 3, 19 November 2007
+`)
+				writeFile("vendor/github.com/some-org/bsd-repo/LICENSE", `
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Neither the name of Some Org nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+`)
+				writeFile("vendor/github.com/some-org/mit-repo/LICENSE.md", `
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+`)
+				writeFile("vendor/github.com/some-org/mit-repo/license.go", "package license\n")
+				writeFile("vendor/github.com/some-org/mit-repo/sub/LICENSE-APACHE", `
+                                 Apache License
+                           Version 2.0, January 2004
+`)
+				writeFile("vendor/github.com/some-org/no-license-repo/main.go", "package main\n")
+				writeFile("vendor/github.com/some-org/spdx-repo/LICENSE", "SPDX-License-Identifier: MPL-2.0\n")
+				writeFile("vendor/golang.org/x/unlocked/LICENSE", "All rights reserved.\n")
+			})
+
+			it("returns the licenses of every vendored project", func() {
+				projects, err := inventory.Collect(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(projects).To(Equal([]dep.ProjectLicenses{
+					{Project: "github.com/some-org/agpl-repo", Licenses: []string{"AGPL-3.0"}},
+					{Project: "github.com/some-org/bsd-repo", Licenses: []string{"BSD-3-Clause"}},
+					{Project: "github.com/some-org/mit-repo", Licenses: []string{"Apache-2.0", "MIT"}},
+					{Project: "github.com/some-org/no-license-repo"},
+					{Project: "github.com/some-org/spdx-repo", Licenses: []string{"MPL-2.0"}},
+					{Project: "golang.org/x/unlocked", Unclassified: []string{"golang.org/x/unlocked/LICENSE"}},
+				}))
+			})
+		})
+
+		context("when the vendor directory has no Gopkg.lock", func() {
+			it.Before(func() {
+				writeFile("vendor/github.com/some-org/some-repo/LICENSE", `
+Permission to use, copy, modify, and/or distribute this software for any
+purpose with or without fee is hereby granted, provided that the above
+`)
+				writeFile("vendor/github.com/some-org/some-repo/internal/COPYING", `
+                    GNU GENERAL PUBLIC LICENSE
+                       Version 2, June 1991
+
+(Some other Free Software Foundation software is covered by
+the GNU Library General Public License instead.)
+`)
+			})
+
+			it("groups the license files by the directory that contains them", func() {
+				projects, err := inventory.Collect(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(projects).To(Equal([]dep.ProjectLicenses{
+					{Project: "github.com/some-org/some-repo", Licenses: []string{"GPL-2.0", "ISC"}},
+				}))
+			})
+		})
+
+		context("when the vendored licenses are full texts that mention other licenses", func() {
+			it.Before(func() {
+				for _, license := range []string{"AGPL-3.0", "GPL-2.0", "GPL-3.0", "LGPL-2.1", "MPL-2.0"} {
+					content, err := os.ReadFile(filepath.Join("testdata", "licenses", license))
+					Expect(err).NotTo(HaveOccurred())
+
+					writeFile(fmt.Sprintf("vendor/github.com/some-org/%s-repo/COPYING", strings.ToLower(license)), string(content))
+				}
+			})
+
+			it("classifies them by their titles rather than the licenses they mention", func() {
+				projects, err := inventory.Collect(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(projects).To(Equal([]dep.ProjectLicenses{
+					{Project: "github.com/some-org/agpl-3.0-repo", Licenses: []string{"AGPL-3.0"}},
+					{Project: "github.com/some-org/gpl-2.0-repo", Licenses: []string{"GPL-2.0"}},
+					{Project: "github.com/some-org/gpl-3.0-repo", Licenses: []string{"GPL-3.0"}},
+					{Project: "github.com/some-org/lgpl-2.1-repo", Licenses: []string{"LGPL-2.1"}},
+					{Project: "github.com/some-org/mpl-2.0-repo", Licenses: []string{"MPL-2.0"}},
+				}))
+			})
+		})
+
+		context("when the app has no vendor directory", func() {
+			it("returns no projects", func() {
+				projects, err := inventory.Collect(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(projects).To(BeEmpty())
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the Gopkg.lock cannot be parsed", func() {
+				it.Before(func() {
+					writeFile("Gopkg.lock", "%%%")
+					writeFile("vendor/github.com/some-org/some-repo/LICENSE", "")
+				})
+
+				it("returns an error", func() {
+					_, err := inventory.Collect(workingDir)
+					Expect(err).To(MatchError(ContainSubstring("failed to parse Gopkg.lock")))
+				})
+			})
+		})
+	})
+}
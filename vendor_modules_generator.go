@@ -0,0 +1,105 @@
+package dep
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// vendorModulesGoVersion is the language version of the synthesized go.mod.
+// Go 1.14 is the first version that builds from vendor by default and checks
+// the requirements against vendor/modules.txt.
+const vendorModulesGoVersion = "1.14"
+
+// gitRevision matches a full or abbreviated git commit hash.
+var gitRevision = regexp.MustCompile(`^[0-9a-f]{12,40}$`)
+
+// VendorModules is a go.mod and vendor/modules.txt that let the go command
+// build a dep vendor directory in module mode.
+type VendorModules struct {
+	GoMod      string
+	ModulesTxt string
+
+	// Unexpressible lists the dep projects that have no module version, along
+	// with how they were handled.
+	Unexpressible []string
+}
+
+type GopkgLockVendorModulesGenerator struct {
+	parser GopkgLockParser
+}
+
+func NewGopkgLockVendorModulesGenerator() GopkgLockVendorModulesGenerator {
+	return GopkgLockVendorModulesGenerator{
+		parser: NewGopkgLockParser(),
+	}
+}
+
+// Generate synthesizes a go.mod for the module at modulePath and a matching
+// vendor/modules.txt from the Gopkg.lock in workingDir. Every locked project
+// is treated as a module that provides the packages locked for it. Projects
+// that are locked to a semantic version keep that version. Any other project
+// is given a pseudo-version from its revision, which is enough to build from
+// vendor without network access but does not identify a real module version.
+func (g GopkgLockVendorModulesGenerator) Generate(workingDir, modulePath string) (VendorModules, error) {
+	projects, err := g.parser.Parse(filepath.Join(workingDir, GopkgLock))
+	if err != nil {
+		return VendorModules{}, err
+	}
+
+	var modules VendorModules
+	var requires []string
+	var modulesTxt strings.Builder
+	for _, project := range projects {
+		version, ok := semanticModuleVersion(project)
+		if !ok {
+			version, ok = pseudoModuleVersion(project, time.Time{})
+			if !ok {
+				modules.Unexpressible = append(modules.Unexpressible,
+					fmt.Sprintf("%s: revision %q is not a git commit and was left out", project.Name, project.Revision))
+				continue
+			}
+
+			reason := "is not locked to a version"
+			switch {
+			case project.Branch != "":
+				reason = fmt.Sprintf("is locked to branch %q", project.Branch)
+			case project.Version != "":
+				reason = fmt.Sprintf("is locked to version %q, which is not a semantic version", project.Version)
+			}
+
+			modules.Unexpressible = append(modules.Unexpressible,
+				fmt.Sprintf("%s: %s, vendored as %s", project.Name, reason, version))
+		}
+
+		requires = append(requires, fmt.Sprintf("%s %s", project.Name, version))
+
+		fmt.Fprintf(&modulesTxt, "# %s %s\n## explicit\n", project.Name, version)
+		for _, pkg := range project.Packages {
+			if pkg == "." {
+				fmt.Fprintf(&modulesTxt, "%s\n", project.Name)
+				continue
+			}
+
+			fmt.Fprintf(&modulesTxt, "%s/%s\n", project.Name, pkg)
+		}
+	}
+
+	var goMod strings.Builder
+	fmt.Fprintf(&goMod, "module %s\n\ngo %s\n", modulePath, vendorModulesGoVersion)
+
+	if len(requires) > 0 {
+		goMod.WriteString("\nrequire (\n")
+		for _, require := range requires {
+			fmt.Fprintf(&goMod, "\t%s\n", require)
+		}
+		goMod.WriteString(")\n")
+	}
+
+	modules.GoMod = goMod.String()
+	modules.ModulesTxt = modulesTxt.String()
+
+	return modules, nil
+}
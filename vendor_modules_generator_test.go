@@ -0,0 +1,221 @@
+package dep_test
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/dep"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testVendorModulesGenerator(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+		generator  dep.GopkgLockVendorModulesGenerator
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = os.MkdirTemp("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = os.WriteFile(filepath.Join(workingDir, "Gopkg.lock"), []byte(`
+[[projects]]
+  branch = "main"
+  name = "github.com/some-org/branch-repo"
+  packages = ["."]
+  revision = "1111111111111111111111111111111111111111"
+
+[[projects]]
+  name = "github.com/some-org/some-repo"
+  packages = [".", "sub"]
+  revision = "2222222222222222222222222222222222222222"
+  version = "v1.4.0"
+
+[[projects]]
+  name = "launchpad.net/some-bzr-repo"
+  packages = ["."]
+  revision = "some-user@example.com-20200101000000-abcdef"
+`), 0600)
+		Expect(err).NotTo(HaveOccurred())
+
+		generator = dep.NewGopkgLockVendorModulesGenerator()
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	context("Generate", func() {
+		it("synthesizes a go.mod and vendor/modules.txt from the Gopkg.lock", func() {
+			modules, err := generator.Generate(workingDir, "github.com/some-org/some-app")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(modules.GoMod).To(Equal(`module github.com/some-org/some-app
+
+go 1.14
+
+require (
+	github.com/some-org/branch-repo v0.0.0-00010101000000-111111111111
+	github.com/some-org/some-repo v1.4.0
+)
+`))
+
+			Expect(modules.ModulesTxt).To(Equal(`# github.com/some-org/branch-repo v0.0.0-00010101000000-111111111111
+## explicit
+github.com/some-org/branch-repo
+# github.com/some-org/some-repo v1.4.0
+## explicit
+github.com/some-org/some-repo
+github.com/some-org/some-repo/sub
+`))
+
+			Expect(modules.Unexpressible).To(Equal([]string{
+				`github.com/some-org/branch-repo: is locked to branch "main", vendored as v0.0.0-00010101000000-111111111111`,
+				`launchpad.net/some-bzr-repo: revision "some-user@example.com-20200101000000-abcdef" is not a git commit and was left out`,
+			}))
+		})
+
+		it("lets the go command build the vendor directory without network access", func() {
+			if _, err := exec.LookPath("go"); err != nil {
+				t.Skip("go is not installed")
+			}
+
+			files := map[string]string{
+				"main.go": `package main
+
+import (
+	"github.com/some-org/branch-repo"
+	"github.com/some-org/some-repo"
+	"github.com/some-org/some-repo/sub"
+)
+
+func main() { branch.Run(); some.Run(); sub.Run() }
+`,
+				"vendor/github.com/some-org/branch-repo/branch.go": "package branch\n\nfunc Run() {}\n",
+				"vendor/github.com/some-org/some-repo/some.go":     "package some\n\nfunc Run() {}\n",
+				"vendor/github.com/some-org/some-repo/sub/sub.go":  "package sub\n\nfunc Run() {}\n",
+			}
+			for path, content := range files {
+				Expect(os.MkdirAll(filepath.Dir(filepath.Join(workingDir, path)), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, path), []byte(content), 0600)).To(Succeed())
+			}
+
+			modules, err := generator.Generate(workingDir, "github.com/some-org/some-app")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(os.WriteFile(filepath.Join(workingDir, "go.mod"), []byte(modules.GoMod), 0600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, "vendor", "modules.txt"), []byte(modules.ModulesTxt), 0600)).To(Succeed())
+
+			buffer := bytes.NewBuffer(nil)
+			cmd := exec.Command("go", "build", "-o", os.DevNull, ".")
+			cmd.Dir = workingDir
+			cmd.Env = append(os.Environ(), "GO111MODULE=on", "GOFLAGS=-mod=vendor", "GOPROXY=off", "GOTOOLCHAIN=local", "GOWORK=off")
+			cmd.Stdout, cmd.Stderr = buffer, buffer
+			Expect(cmd.Run()).To(Succeed(), buffer.String())
+		})
+
+		context("when projects are imported from gopkg.in", func() {
+			it.Before(func() {
+				err := os.WriteFile(filepath.Join(workingDir, "Gopkg.lock"), []byte(`
+[[projects]]
+  name = "gopkg.in/go-playground/validator.v9"
+  packages = ["."]
+  revision = "3333333333333333333333333333333333333333"
+  version = "v9.31.0"
+
+[[projects]]
+  name = "gopkg.in/yaml.v2"
+  packages = ["."]
+  revision = "4444444444444444444444444444444444444444"
+`), 0600)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			it("gives them the major version of their import path", func() {
+				modules, err := generator.Generate(workingDir, "github.com/some-org/some-app")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(modules.GoMod).To(Equal(`module github.com/some-org/some-app
+
+go 1.14
+
+require (
+	gopkg.in/go-playground/validator.v9 v9.31.0
+	gopkg.in/yaml.v2 v2.0.0-00010101000000-444444444444
+)
+`))
+			})
+
+			it("lets the go command build the vendor directory", func() {
+				if _, err := exec.LookPath("go"); err != nil {
+					t.Skip("go is not installed")
+				}
+
+				files := map[string]string{
+					"main.go": `package main
+
+import (
+	"gopkg.in/go-playground/validator.v9"
+	"gopkg.in/yaml.v2"
+)
+
+func main() { validator.Run(); yaml.Run() }
+`,
+					"vendor/gopkg.in/go-playground/validator.v9/validator.go": "package validator\n\nfunc Run() {}\n",
+					"vendor/gopkg.in/yaml.v2/yaml.go":                         "package yaml\n\nfunc Run() {}\n",
+				}
+				for path, content := range files {
+					Expect(os.MkdirAll(filepath.Dir(filepath.Join(workingDir, path)), os.ModePerm)).To(Succeed())
+					Expect(os.WriteFile(filepath.Join(workingDir, path), []byte(content), 0600)).To(Succeed())
+				}
+
+				modules, err := generator.Generate(workingDir, "github.com/some-org/some-app")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(os.WriteFile(filepath.Join(workingDir, "go.mod"), []byte(modules.GoMod), 0600)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "vendor", "modules.txt"), []byte(modules.ModulesTxt), 0600)).To(Succeed())
+
+				buffer := bytes.NewBuffer(nil)
+				cmd := exec.Command("go", "build", "-o", os.DevNull, ".")
+				cmd.Dir = workingDir
+				cmd.Env = append(os.Environ(), "GO111MODULE=on", "GOFLAGS=-mod=vendor", "GOPROXY=off", "GOTOOLCHAIN=local", "GOWORK=off")
+				cmd.Stdout, cmd.Stderr = buffer, buffer
+				Expect(cmd.Run()).To(Succeed(), buffer.String())
+			})
+		})
+
+		context("when the app has no Gopkg.lock", func() {
+			it.Before(func() {
+				Expect(os.Remove(filepath.Join(workingDir, "Gopkg.lock"))).To(Succeed())
+			})
+
+			it("synthesizes a go.mod without requirements", func() {
+				modules, err := generator.Generate(workingDir, "app")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(modules.GoMod).To(Equal("module app\n\ngo 1.14\n"))
+				Expect(modules.ModulesTxt).To(BeEmpty())
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the Gopkg.lock cannot be parsed", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "Gopkg.lock"), []byte("%%%"), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := generator.Generate(workingDir, "app")
+					Expect(err).To(MatchError(ContainSubstring("failed to parse Gopkg.lock")))
+				})
+			})
+		})
+	})
+}
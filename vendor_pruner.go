@@ -0,0 +1,170 @@
+package dep
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	PruneUnusedPackages = "unused-packages"
+	PruneNonGo          = "non-go"
+	PruneGoTests        = "go-tests"
+)
+
+// ParsePruneOptions parses a comma or whitespace separated list of the prune
+// options that dep supports in Gopkg.toml. The options are returned sorted
+// and without duplicates.
+func ParsePruneOptions(value string) ([]string, error) {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+
+	seen := map[string]bool{}
+	var options []string
+	for _, field := range fields {
+		option := strings.ToLower(field)
+		switch option {
+		case PruneUnusedPackages, PruneNonGo, PruneGoTests:
+		default:
+			return nil, fmt.Errorf("unknown prune option %q, expected one of %s, %s, or %s", field, PruneUnusedPackages, PruneNonGo, PruneGoTests)
+		}
+
+		if !seen[option] {
+			seen[option] = true
+			options = append(options, option)
+		}
+	}
+
+	sort.Strings(options)
+
+	return options, nil
+}
+
+type GopkgLockVendorPruner struct {
+	parser GopkgLockParser
+}
+
+func NewGopkgLockVendorPruner() GopkgLockVendorPruner {
+	return GopkgLockVendorPruner{
+		parser: NewGopkgLockParser(),
+	}
+}
+
+// Prune removes files from the copies of the projects in the Gopkg.lock of
+// workingDir that are vendored in vendorDir, the same way that dep prunes
+// them:
+//
+//   - unused-packages removes the files of directories that are not one of
+//     the locked packages of a project
+//   - non-go removes every file that is not a .go file
+//   - go-tests removes every _test.go file
+//
+// License and other legal files are always kept and directories that are
+// left empty are removed. Vendored directories that do not belong to a
+// locked project are not touched.
+func (p GopkgLockVendorPruner) Prune(workingDir, vendorDir string, options []string) error {
+	if len(options) == 0 {
+		return nil
+	}
+
+	projects, err := p.parser.Parse(filepath.Join(workingDir, GopkgLock))
+	if err != nil {
+		return err
+	}
+
+	prune := map[string]bool{}
+	for _, option := range options {
+		prune[option] = true
+	}
+
+	for _, project := range projects {
+		projectDir := filepath.Join(vendorDir, filepath.FromSlash(project.Name))
+
+		packages := map[string]bool{}
+		for _, pkg := range project.Packages {
+			packages[filepath.ToSlash(filepath.Clean(pkg))] = true
+		}
+
+		var dirs []string
+		err := filepath.WalkDir(projectDir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() {
+				dirs = append(dirs, path)
+				return nil
+			}
+
+			if isLegalFile(d.Name()) {
+				return nil
+			}
+
+			rel, err := filepath.Rel(projectDir, filepath.Dir(path))
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case prune[PruneUnusedPackages] && !packages[filepath.ToSlash(rel)],
+				prune[PruneNonGo] && !strings.HasSuffix(d.Name(), ".go"),
+				prune[PruneGoTests] && strings.HasSuffix(d.Name(), "_test.go"):
+				return os.Remove(path)
+			}
+
+			return nil
+		})
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			return fmt.Errorf("failed to prune vendored project %s: %w", project.Name, err)
+		}
+
+		// The directories are visited parents first, so they are removed in
+		// reverse to let a parent become empty once its children are gone.
+		for i := len(dirs) - 1; i >= 0; i-- {
+			entries, err := os.ReadDir(dirs[i])
+			if err != nil {
+				return fmt.Errorf("failed to prune vendored project %s: %w", project.Name, err)
+			}
+
+			if len(entries) == 0 {
+				err = os.Remove(dirs[i])
+				if err != nil {
+					return fmt.Errorf("failed to prune vendored project %s: %w", project.Name, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// isLegalFile reports whether a file carries licensing or attribution
+// information that dep keeps regardless of the prune options.
+func isLegalFile(name string) bool {
+	if isLicenseFile(name) {
+		return true
+	}
+
+	upper := strings.ToUpper(name)
+	if strings.HasSuffix(upper, ".GO") {
+		return false
+	}
+
+	for _, substring := range []string{"AUTHORS", "CONTRIBUTORS", "COPYRIGHT", "COPYLEFT", "LEGAL", "NOTICE", "DISCLAIMER", "PATENT", "THIRD-PARTY", "THIRDPARTY"} {
+		if strings.Contains(upper, substring) {
+			return true
+		}
+	}
+
+	return false
+}
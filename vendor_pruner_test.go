@@ -0,0 +1,180 @@
+package dep_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/dep"
+	"github.com/paketo-buildpacks/packit/v2/fs"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testVendorPruner(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+		pruner     dep.GopkgLockVendorPruner
+	)
+
+	writeFile := func(path string) {
+		path = filepath.Join(workingDir, filepath.FromSlash(path))
+		Expect(os.MkdirAll(filepath.Dir(path), os.ModePerm)).To(Succeed())
+		Expect(os.WriteFile(path, nil, 0600)).To(Succeed())
+	}
+
+	vendored := func(path string) string {
+		return filepath.Join(workingDir, "vendor", filepath.FromSlash(path))
+	}
+
+	it.Before(func() {
+		var err error
+		workingDir, err = os.MkdirTemp("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = os.WriteFile(filepath.Join(workingDir, "Gopkg.lock"), []byte(`
+[[projects]]
+  name = "github.com/some-org/some-repo"
+  packages = [".", "sub"]
+  revision = "2222222222222222222222222222222222222222"
+`), 0600)
+		Expect(err).NotTo(HaveOccurred())
+
+		writeFile("vendor/github.com/some-org/some-repo/LICENSE")
+		writeFile("vendor/github.com/some-org/some-repo/README.md")
+		writeFile("vendor/github.com/some-org/some-repo/some-repo.go")
+		writeFile("vendor/github.com/some-org/some-repo/some-repo_test.go")
+		writeFile("vendor/github.com/some-org/some-repo/sub/sub.go")
+		writeFile("vendor/github.com/some-org/some-repo/sub/testdata/fixture.json")
+		writeFile("vendor/github.com/some-org/some-repo/unused/unused.go")
+		writeFile("vendor/github.com/some-org/some-repo/unused/NOTICE")
+		writeFile("vendor/github.com/some-org/some-repo/cmd/tool/main.go")
+		writeFile("vendor/github.com/other-org/unlocked-repo/README.md")
+
+		pruner = dep.NewGopkgLockVendorPruner()
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	context("Prune", func() {
+		it("removes the files of packages that are not locked", func() {
+			err := pruner.Prune(workingDir, filepath.Join(workingDir, "vendor"), []string{dep.PruneUnusedPackages})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(vendored("github.com/some-org/some-repo/some-repo.go")).To(BeARegularFile())
+			Expect(vendored("github.com/some-org/some-repo/sub/sub.go")).To(BeARegularFile())
+			Expect(vendored("github.com/some-org/some-repo/unused/unused.go")).NotTo(BeAnExistingFile())
+			Expect(vendored("github.com/some-org/some-repo/unused/NOTICE")).To(BeARegularFile())
+			Expect(vendored("github.com/some-org/some-repo/sub/testdata")).NotTo(BeAnExistingFile())
+			Expect(vendored("github.com/some-org/some-repo/cmd")).NotTo(BeAnExistingFile())
+			Expect(vendored("github.com/other-org/unlocked-repo/README.md")).To(BeARegularFile())
+		})
+
+		it("removes the files that are not Go files", func() {
+			err := pruner.Prune(workingDir, filepath.Join(workingDir, "vendor"), []string{dep.PruneNonGo})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(vendored("github.com/some-org/some-repo/LICENSE")).To(BeARegularFile())
+			Expect(vendored("github.com/some-org/some-repo/README.md")).NotTo(BeAnExistingFile())
+			Expect(vendored("github.com/some-org/some-repo/some-repo_test.go")).To(BeARegularFile())
+			Expect(vendored("github.com/some-org/some-repo/sub/testdata")).NotTo(BeAnExistingFile())
+			Expect(vendored("github.com/some-org/some-repo/unused/unused.go")).To(BeARegularFile())
+		})
+
+		it("removes the Go test files", func() {
+			err := pruner.Prune(workingDir, filepath.Join(workingDir, "vendor"), []string{dep.PruneGoTests})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(vendored("github.com/some-org/some-repo/some-repo.go")).To(BeARegularFile())
+			Expect(vendored("github.com/some-org/some-repo/some-repo_test.go")).NotTo(BeAnExistingFile())
+			Expect(vendored("github.com/some-org/some-repo/README.md")).To(BeARegularFile())
+		})
+
+		context("when no options are given", func() {
+			it("does not touch vendor", func() {
+				err := pruner.Prune(workingDir, filepath.Join(workingDir, "vendor"), nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(vendored("github.com/some-org/some-repo/unused/unused.go")).To(BeARegularFile())
+				Expect(vendored("github.com/some-org/some-repo/some-repo_test.go")).To(BeARegularFile())
+			})
+		})
+
+		context("when the vendor directory is outside of the working directory", func() {
+			var vendorDir string
+
+			it.Before(func() {
+				var err error
+				vendorDir, err = os.MkdirTemp("", "vendor")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fs.Copy(filepath.Join(workingDir, "vendor"), filepath.Join(vendorDir, "vendor"))).To(Succeed())
+			})
+
+			it.After(func() {
+				Expect(os.RemoveAll(vendorDir)).To(Succeed())
+			})
+
+			it("prunes it by the Gopkg.lock of the working directory and leaves the vendor of the app alone", func() {
+				err := pruner.Prune(workingDir, filepath.Join(vendorDir, "vendor"), []string{dep.PruneUnusedPackages})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(filepath.Join(vendorDir, "vendor", "github.com", "some-org", "some-repo", "sub", "sub.go")).To(BeARegularFile())
+				Expect(filepath.Join(vendorDir, "vendor", "github.com", "some-org", "some-repo", "unused", "unused.go")).NotTo(BeAnExistingFile())
+				Expect(vendored("github.com/some-org/some-repo/unused/unused.go")).To(BeARegularFile())
+			})
+		})
+
+		context("when a locked project is not vendored", func() {
+			it.Before(func() {
+				Expect(os.RemoveAll(filepath.Join(workingDir, "vendor"))).To(Succeed())
+			})
+
+			it("skips it", func() {
+				err := pruner.Prune(workingDir, filepath.Join(workingDir, "vendor"), []string{dep.PruneUnusedPackages})
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the Gopkg.lock cannot be parsed", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "Gopkg.lock"), []byte("%%%"), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					err := pruner.Prune(workingDir, filepath.Join(workingDir, "vendor"), []string{dep.PruneNonGo})
+					Expect(err).To(MatchError(ContainSubstring("failed to parse Gopkg.lock")))
+				})
+			})
+		})
+	})
+
+	context("ParsePruneOptions", func() {
+		it("returns the sorted options without duplicates", func() {
+			options, err := dep.ParsePruneOptions("unused-packages, Go-Tests unused-packages,non-go")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(options).To(Equal([]string{"go-tests", "non-go", "unused-packages"}))
+		})
+
+		it("returns no options for an empty value", func() {
+			options, err := dep.ParsePruneOptions("")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(options).To(BeEmpty())
+		})
+
+		context("failure cases", func() {
+			context("when an option is not supported", func() {
+				it("returns an error", func() {
+					_, err := dep.ParsePruneOptions("unused-packages,some-option")
+					Expect(err).To(MatchError(`unknown prune option "some-option", expected one of unused-packages, non-go, or go-tests`))
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,335 @@
+package dep
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// depHashVersion is the version of the vendored tree hash that dep records
+// in the digest of each project in Gopkg.lock.
+const depHashVersion = 1
+
+const (
+	VendorAdded    = "added"
+	VendorRemoved  = "removed"
+	VendorModified = "modified"
+)
+
+// VendorChange describes how the vendored copy of a project differs from
+// Gopkg.lock.
+type VendorChange struct {
+	Project string
+	Change  string
+
+	// Packages lists the packages of a modified project that were added or
+	// removed compared to the packages in Gopkg.lock, prefixed with "+" and
+	// "-" respectively.
+	Packages []string
+}
+
+type GopkgLockVendorVerifier struct {
+	parser GopkgLockParser
+}
+
+func NewGopkgLockVendorVerifier() GopkgLockVendorVerifier {
+	return GopkgLockVendorVerifier{
+		parser: NewGopkgLockParser(),
+	}
+}
+
+// Verify compares the vendor directory in workingDir against the projects in
+// its Gopkg.lock without running dep. Each locked project is hashed the same
+// way that dep hashes it for the digest field, so a project whose hash does
+// not match is reported as modified. Locked projects that are missing from
+// vendor are reported as removed and vendored projects that are not locked as
+// added.
+func (v GopkgLockVendorVerifier) Verify(workingDir string) ([]VendorChange, error) {
+	projects, err := v.parser.Parse(filepath.Join(workingDir, GopkgLock))
+	if err != nil {
+		return nil, err
+	}
+
+	vendorDir := filepath.Join(workingDir, "vendor")
+
+	var changes []VendorChange
+	for _, project := range projects {
+		projectDir := filepath.Join(vendorDir, filepath.FromSlash(project.Name))
+
+		info, err := os.Stat(projectDir)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				changes = append(changes, VendorChange{Project: project.Name, Change: VendorRemoved})
+				continue
+			}
+
+			return nil, fmt.Errorf("failed to verify vendored project %s: %w", project.Name, err)
+		}
+
+		if !info.IsDir() {
+			changes = append(changes, VendorChange{Project: project.Name, Change: VendorModified})
+			continue
+		}
+
+		// Gopkg.lock files written by dep before v0.5.0 have no digests, so
+		// only the presence of their projects can be verified.
+		if project.Digest == "" {
+			continue
+		}
+
+		version, _, _ := strings.Cut(project.Digest, ":")
+		if version != strconv.Itoa(depHashVersion) {
+			return nil, fmt.Errorf("failed to verify vendored project %s: unsupported digest %q in Gopkg.lock", project.Name, project.Digest)
+		}
+
+		digest, err := vendorDigest(projectDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify vendored project %s: %w", project.Name, err)
+		}
+
+		if digest == project.Digest {
+			continue
+		}
+
+		packages, err := packageChanges(projectDir, project.Packages)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify vendored project %s: %w", project.Name, err)
+		}
+
+		changes = append(changes, VendorChange{
+			Project:  project.Name,
+			Change:   VendorModified,
+			Packages: packages,
+		})
+	}
+
+	var locked []string
+	for _, project := range projects {
+		locked = append(locked, project.Name)
+	}
+
+	added, err := unlockedProjects(vendorDir, "", locked)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify vendor: %w", err)
+	}
+
+	for _, project := range added {
+		changes = append(changes, VendorChange{Project: project, Change: VendorAdded})
+	}
+
+	return changes, nil
+}
+
+// vendorDigest computes the digest of the vendored project in dir in the same
+// way as dep's verify.DigestFromDirectory. The tree is walked breadth first
+// in lexical order, skipping nested vendor and VCS directories, and the hash
+// covers the slash separated relative path, the type, and the contents of
+// every node, which for a symlink is its target. Line endings are normalized so that the digest does not depend
+// on the platform that the project was checked out on.
+func vendorDigest(dir string) (string, error) {
+	dir = filepath.Clean(dir)
+	h := sha256.New()
+	modeBytes := make([]byte, 4)
+
+	queue := []string{""}
+	for len(queue) > 0 {
+		var relative string
+		relative, queue = queue[0], queue[1:]
+		path := filepath.Join(dir, relative)
+
+		switch filepath.Base(relative) {
+		case "vendor", ".bzr", ".git", ".hg", ".svn":
+			continue
+		}
+
+		info, err := os.Lstat(path)
+		if err != nil {
+			return "", err
+		}
+
+		var mode os.FileMode
+		var skip bool
+		modeType := info.Mode() & os.ModeType
+		switch {
+		case modeType&os.ModeDir > 0:
+			mode, skip = os.ModeDir, true
+		case modeType&os.ModeNamedPipe > 0:
+			mode, skip = os.ModeNamedPipe, true
+		case modeType&os.ModeSocket > 0:
+			mode, skip = os.ModeSocket, true
+		case modeType&os.ModeDevice > 0:
+			mode, skip = os.ModeDevice, true
+		case modeType&os.ModeSymlink > 0:
+			mode = os.ModeSymlink
+		}
+
+		writeWithNull(h, []byte(filepath.ToSlash(relative)))
+		binary.LittleEndian.PutUint32(modeBytes, uint32(mode))
+		writeWithNull(h, modeBytes)
+
+		if info.IsDir() {
+			children, err := os.ReadDir(path)
+			if err != nil {
+				return "", err
+			}
+
+			var names []string
+			for _, child := range children {
+				names = append(names, child.Name())
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				queue = append(queue, filepath.Join(relative, name))
+			}
+		}
+
+		if skip {
+			continue
+		}
+
+		if modeType&os.ModeSymlink > 0 {
+			target, err := os.Readlink(path)
+			if err != nil {
+				return "", err
+			}
+
+			writeWithNull(h, []byte(filepath.ToSlash(target)))
+			continue
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+
+		content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+		_, _ = h.Write(content)
+		writeWithNull(h, []byte(strconv.Itoa(len(content))))
+	}
+
+	return fmt.Sprintf("%d:%s", depHashVersion, hex.EncodeToString(h.Sum(nil))), nil
+}
+
+func writeWithNull(h hash.Hash, data []byte) {
+	_, _ = h.Write(data)
+	_, _ = h.Write([]byte{0})
+}
+
+// packageChanges compares the Go packages found in the vendored project in dir
+// with the packages that are locked for it.
+func packageChanges(dir string, locked []string) ([]string, error) {
+	var vendored []string
+	err := filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			switch entry.Name() {
+			case "vendor", "testdata", ".bzr", ".git", ".hg", ".svn":
+				if path != dir {
+					return filepath.SkipDir
+				}
+			}
+
+			return nil
+		}
+
+		if filepath.Ext(path) != ".go" {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+
+		pkg := filepath.ToSlash(rel)
+		if !contains(vendored, pkg) {
+			vendored = append(vendored, pkg)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []string
+	for _, pkg := range vendored {
+		if !contains(locked, pkg) {
+			changes = append(changes, "+"+pkg)
+		}
+	}
+
+	for _, pkg := range locked {
+		if !contains(vendored, pkg) {
+			changes = append(changes, "-"+pkg)
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i][1:] < changes[j][1:]
+	})
+
+	return changes, nil
+}
+
+// unlockedProjects returns the directories under vendorDir/relative that are
+// neither a locked project nor contain one.
+func unlockedProjects(vendorDir, relative string, locked []string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(vendorDir, filepath.FromSlash(relative)))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var unlocked []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if relative != "" {
+			name = relative + "/" + name
+		}
+
+		if contains(locked, name) {
+			continue
+		}
+
+		var parent bool
+		for _, project := range locked {
+			if strings.HasPrefix(project, name+"/") {
+				parent = true
+				break
+			}
+		}
+
+		if !parent || !entry.IsDir() {
+			if entry.IsDir() {
+				unlocked = append(unlocked, name)
+			}
+			continue
+		}
+
+		nested, err := unlockedProjects(vendorDir, name, locked)
+		if err != nil {
+			return nil, err
+		}
+
+		unlocked = append(unlocked, nested...)
+	}
+
+	return unlocked, nil
+}
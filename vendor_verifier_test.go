@@ -0,0 +1,228 @@
+package dep_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/dep"
+	"github.com/paketo-buildpacks/packit/v2/fs"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testVendorVerifier(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+		projectDir string
+		verifier   dep.GopkgLockVendorVerifier
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = os.MkdirTemp("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+
+		projectDir = filepath.Join(workingDir, "vendor", "github.com", "some-org", "some-repo")
+		Expect(os.MkdirAll(filepath.Join(projectDir, "sub"), os.ModePerm)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(projectDir, ".git"), os.ModePerm)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(projectDir, "some.go"), []byte("package some\n"), 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(projectDir, "sub", "sub.go"), []byte("package sub\n"), 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(projectDir, ".git", "HEAD"), []byte("x\n"), 0600)).To(Succeed())
+
+		err = os.WriteFile(filepath.Join(workingDir, "Gopkg.lock"), []byte(`
+[[projects]]
+  digest = "1:7d8d964f8828005cf70da10d98ea759a0f6c90c2a88aa760cbfab8fae9086773"
+  name = "github.com/some-org/some-repo"
+  packages = [".", "sub"]
+  pruneopts = "UT"
+  revision = "some-revision"
+`), 0600)
+		Expect(err).NotTo(HaveOccurred())
+
+		verifier = dep.NewGopkgLockVendorVerifier()
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	context("Verify", func() {
+		it("reports no changes when vendor matches the digests in the Gopkg.lock", func() {
+			changes, err := verifier.Verify(workingDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(BeEmpty())
+		})
+
+		context("when a vendored file has Windows line endings", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(projectDir, "some.go"), []byte("package some\r\n"), 0600)).To(Succeed())
+			})
+
+			it("hashes it the same as dep does", func() {
+				changes, err := verifier.Verify(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(changes).To(BeEmpty())
+			})
+		})
+
+		context("when a vendored project was modified", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(projectDir, "some.go"), []byte("package other\n"), 0600)).To(Succeed())
+				Expect(os.RemoveAll(filepath.Join(projectDir, "sub"))).To(Succeed())
+				Expect(os.MkdirAll(filepath.Join(projectDir, "extra"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(projectDir, "extra", "extra.go"), []byte("package extra\n"), 0600)).To(Succeed())
+			})
+
+			it("reports the project along with the added and removed packages", func() {
+				changes, err := verifier.Verify(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(changes).To(Equal([]dep.VendorChange{
+					{
+						Project:  "github.com/some-org/some-repo",
+						Change:   dep.VendorModified,
+						Packages: []string{"+extra", "-sub"},
+					},
+				}))
+			})
+		})
+
+		context("when a locked project is missing from vendor", func() {
+			it.Before(func() {
+				Expect(os.RemoveAll(projectDir)).To(Succeed())
+			})
+
+			it("reports the project as removed", func() {
+				changes, err := verifier.Verify(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(changes).To(Equal([]dep.VendorChange{
+					{Project: "github.com/some-org/some-repo", Change: dep.VendorRemoved},
+				}))
+			})
+		})
+
+		context("when vendor contains projects that are not locked", func() {
+			it.Before(func() {
+				Expect(os.MkdirAll(filepath.Join(workingDir, "vendor", "github.com", "some-org", "other-repo"), os.ModePerm)).To(Succeed())
+				Expect(os.MkdirAll(filepath.Join(workingDir, "vendor", "golang.org", "x", "sys"), os.ModePerm)).To(Succeed())
+			})
+
+			it("reports the projects as added", func() {
+				changes, err := verifier.Verify(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(changes).To(Equal([]dep.VendorChange{
+					{Project: "github.com/some-org/other-repo", Change: dep.VendorAdded},
+					{Project: "golang.org", Change: dep.VendorAdded},
+				}))
+			})
+		})
+
+		context("when vendor was written by dep ensure", func() {
+			it.Before(func() {
+				Expect(os.RemoveAll(workingDir)).To(Succeed())
+
+				// The digest in this Gopkg.lock was written by dep ensure, so it checks
+				// the hashing against dep itself rather than against vendorDigest.
+				Expect(fs.Copy(filepath.Join("testdata", "vendored-app"), workingDir)).To(Succeed())
+			})
+
+			it("reports no changes", func() {
+				changes, err := verifier.Verify(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(changes).To(BeEmpty())
+			})
+
+			context("when a vendored file has Windows line endings", func() {
+				it.Before(func() {
+					path := filepath.Join(workingDir, "vendor", "github.com", "go-errors", "errors", "error.go")
+
+					content, err := os.ReadFile(path)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(os.WriteFile(path, bytes.ReplaceAll(content, []byte("\n"), []byte("\r\n")), 0600)).To(Succeed())
+				})
+
+				it("reports no changes", func() {
+					changes, err := verifier.Verify(workingDir)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(changes).To(BeEmpty())
+				})
+			})
+
+			context("when a vendored file was modified", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "vendor", "github.com", "go-errors", "errors", "README.md"), nil, 0600)).To(Succeed())
+				})
+
+				it("reports the project as modified", func() {
+					changes, err := verifier.Verify(workingDir)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(changes).To(Equal([]dep.VendorChange{
+						{Project: "github.com/go-errors/errors", Change: dep.VendorModified},
+					}))
+				})
+			})
+		})
+
+		context("when a vendored project contains a symlink", func() {
+			it.Before(func() {
+				Expect(os.RemoveAll(filepath.Join(workingDir, "vendor"))).To(Succeed())
+
+				projectDir = filepath.Join(workingDir, "vendor", "github.com", "some-org", "linked-repo")
+				Expect(os.MkdirAll(projectDir, os.ModePerm)).To(Succeed())
+				Expect(os.Symlink(filepath.Join("..", "LICENSE"), filepath.Join(projectDir, "LICENSE"))).To(Succeed())
+
+				// dep hashes the type of every node and the target of a symlink, so
+				// this is the sha256 of "", the type of a directory, "LICENSE", the
+				// type of a symlink and "../LICENSE", each followed by a null byte.
+				err := os.WriteFile(filepath.Join(workingDir, "Gopkg.lock"), []byte(`
+[[projects]]
+  digest = "1:7959187c085f17cae30a3db22391c59f7594f33c19fd73da6632f03082119fa1"
+  name = "github.com/some-org/linked-repo"
+  packages = ["."]
+  pruneopts = ""
+  revision = "some-revision"
+`), 0600)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			it("hashes the symlink the same as dep does", func() {
+				changes, err := verifier.Verify(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(changes).To(BeEmpty())
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the Gopkg.lock cannot be parsed", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "Gopkg.lock"), []byte("%%%"), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := verifier.Verify(workingDir)
+					Expect(err).To(MatchError(ContainSubstring("failed to parse Gopkg.lock")))
+				})
+			})
+
+			context("when a digest uses an unsupported hash version", func() {
+				it.Before(func() {
+					err := os.WriteFile(filepath.Join(workingDir, "Gopkg.lock"), []byte(`
+[[projects]]
+  digest = "2:some-digest"
+  name = "github.com/some-org/some-repo"
+`), 0600)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				it("returns an error", func() {
+					_, err := verifier.Verify(workingDir)
+					Expect(err).To(MatchError(`failed to verify vendored project github.com/some-org/some-repo: unsupported digest "2:some-digest" in Gopkg.lock`))
+				})
+			})
+		})
+	})
+}